@@ -51,8 +51,13 @@ func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Status.ControlPlaneReady = restored.Status.ControlPlaneReady
 	dst.Status.FailureDomains = restored.Status.FailureDomains
 	dst.Spec.Paused = restored.Spec.Paused
+	dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
+	dst.Spec.InfrastructureReadyTimeout = restored.Spec.InfrastructureReadyTimeout
+	dst.Spec.DisableMachineHealthChecks = restored.Spec.DisableMachineHealthChecks
 	dst.Status.Conditions = restored.Status.Conditions
 	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+	dst.Status.LastReconcileTime = restored.Status.LastReconcileTime
+	dst.Status.ConsecutiveReconcileErrors = restored.Status.ConsecutiveReconcileErrors
 
 	return nil
 }
@@ -129,6 +134,7 @@ func restoreMachineSpec(restored *v1alpha3.MachineSpec, dst *v1alpha3.MachineSpe
 	}
 	dst.Bootstrap.DataSecretName = restored.Bootstrap.DataSecretName
 	dst.FailureDomain = restored.FailureDomain
+	dst.NodeDrainTimeout = restored.NodeDrainTimeout
 }
 
 func (dst *Machine) ConvertFrom(srcRaw conversion.Hub) error {
@@ -56,6 +56,23 @@ type ClusterSpec struct {
 	// for provisioning infrastructure for a cluster in said provider.
 	// +optional
 	InfrastructureRef *corev1.ObjectReference `json:"infrastructureRef,omitempty"`
+
+	// NodeDrainTimeout is the default total amount of time that the controller will spend on draining the nodes
+	// of the Cluster; it is propagated to the Machines of the Cluster which do not define their own NodeDrainTimeout,
+	// and updated on them when it changes.
+	// +optional
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// InfrastructureReadyTimeout is how long the infrastructure object of the Cluster is expected to take to become
+	// ready since the Cluster creation; once exceeded, the InfrastructureReadyCondition reports the timeout,
+	// while the controller keeps waiting for the infrastructure object.
+	// +optional
+	InfrastructureReadyTimeout *metav1.Duration `json:"infrastructureReadyTimeout,omitempty"`
+
+	// DisableMachineHealthChecks pauses the remediation of the Machines of the Cluster by MachineHealthChecks,
+	// e.g. during maintenance, by setting the SkipRemediationAnnotation on the worker Machines of the Cluster.
+	// +optional
+	DisableMachineHealthChecks *bool `json:"disableMachineHealthChecks,omitempty"`
 }
 
 // ANCHOR_END: ClusterSpec
@@ -142,6 +159,15 @@ type ClusterStatus struct {
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastReconcileTime is the last time the cluster has been reconciled by the controller.
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// ConsecutiveReconcileErrors is the number of consecutive reconciliations of the cluster
+	// that returned an error; it is reset to zero after a successful reconciliation.
+	// +optional
+	ConsecutiveReconcileErrors int32 `json:"consecutiveReconcileErrors,omitempty"`
 }
 
 // ANCHOR_END: ClusterStatus
@@ -17,10 +17,16 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"strconv"
+	"time"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -44,6 +50,13 @@ func (c *Cluster) Default() {
 	if c.Spec.ControlPlaneRef != nil && len(c.Spec.ControlPlaneRef.Namespace) == 0 {
 		c.Spec.ControlPlaneRef.Namespace = c.Namespace
 	}
+
+	// Add the finalizer when the Cluster is created, so the Cluster controller does not need an additional
+	// round-trip to add it; existing Clusters are left untouched, so the finalizer is never added back
+	// once removed during deletion.
+	if c.CreationTimestamp.IsZero() {
+		controllerutil.AddFinalizer(c, ClusterFinalizer)
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
@@ -87,6 +100,83 @@ func (c *Cluster) validate() error {
 
 	}
 
+	if c.Spec.InfrastructureReadyTimeout != nil && c.Spec.InfrastructureReadyTimeout.Duration <= 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "infrastructureReadyTimeout"),
+				c.Spec.InfrastructureReadyTimeout.Duration.String(),
+				"must be a positive duration",
+			),
+		)
+	}
+
+	if value, ok := c.Annotations[DeleteGraceSecondsAnnotation]; ok {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err != nil || seconds < 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("metadata", "annotations", DeleteGraceSecondsAnnotation),
+					value,
+					"must be a non-negative integer",
+				),
+			)
+		}
+	}
+
+	if value, ok := c.Annotations[ReconcileIntervalAnnotation]; ok {
+		if interval, err := time.ParseDuration(value); err != nil || interval <= 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("metadata", "annotations", ReconcileIntervalAnnotation),
+					value,
+					"must be a positive duration, e.g. 5m",
+				),
+			)
+		}
+	}
+
+	if value, ok := c.Annotations[ReconcileFreezeUntilAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("metadata", "annotations", ReconcileFreezeUntilAnnotation),
+					value,
+					"must be an RFC3339 timestamp, e.g. 2020-01-01T00:00:00Z",
+				),
+			)
+		}
+	}
+
+	if value, ok := c.Annotations[InfraAPIVersionAnnotation]; ok {
+		if gv, err := schema.ParseGroupVersion(value); err != nil || gv.Version == "" ||
+			(c.Spec.InfrastructureRef != nil && gv.Group != c.Spec.InfrastructureRef.GroupVersionKind().Group) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("metadata", "annotations", InfraAPIVersionAnnotation),
+					value,
+					"must be an API version in the group of the infrastructure reference, e.g. infrastructure.cluster.x-k8s.io/v1alpha4",
+				),
+			)
+		}
+	}
+
+	if value, ok := c.Annotations[AdoptInfrastructureAnnotation]; ok {
+		if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("metadata", "annotations", AdoptInfrastructureAnnotation),
+					value,
+					"must be the name of an infrastructure object",
+				),
+			)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
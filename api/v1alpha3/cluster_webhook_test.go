@@ -18,6 +18,7 @@ package v1alpha3
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -41,6 +42,33 @@ func TestClusterDefault(t *testing.T) {
 
 	g.Expect(c.Spec.InfrastructureRef.Namespace).To(Equal(c.Namespace))
 	g.Expect(c.Spec.ControlPlaneRef.Namespace).To(Equal(c.Namespace))
+	g.Expect(c.Finalizers).To(ConsistOf(ClusterFinalizer))
+}
+
+func TestClusterDefaultFinalizer(t *testing.T) {
+	g := NewWithT(t)
+
+	// The finalizer is added once, when the Cluster is created.
+	c := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fooboo",
+		},
+	}
+	c.Default()
+	c.Default()
+	g.Expect(c.Finalizers).To(ConsistOf(ClusterFinalizer))
+
+	// The finalizer is not added back to an existing Cluster, e.g. after it was removed during deletion.
+	deletionTimestamp := metav1.Now()
+	existing := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "fooboo",
+			CreationTimestamp: metav1.Now(),
+			DeletionTimestamp: &deletionTimestamp,
+		},
+	}
+	existing.Default()
+	g.Expect(existing.Finalizers).To(BeEmpty())
 }
 
 func TestClusterValidation(t *testing.T) {
@@ -63,11 +91,108 @@ func TestClusterValidation(t *testing.T) {
 	invalidCPNamespace := valid.DeepCopy()
 	invalidCPNamespace.Spec.InfrastructureRef.Namespace = "baz"
 
+	validDeleteGraceSeconds := valid.DeepCopy()
+	validDeleteGraceSeconds.Annotations = map[string]string{DeleteGraceSecondsAnnotation: "30"}
+
+	invalidDeleteGraceSeconds := valid.DeepCopy()
+	invalidDeleteGraceSeconds.Annotations = map[string]string{DeleteGraceSecondsAnnotation: "-1"}
+
+	validReconcileInterval := valid.DeepCopy()
+	validReconcileInterval.Annotations = map[string]string{ReconcileIntervalAnnotation: "5m"}
+
+	invalidReconcileInterval := valid.DeepCopy()
+	invalidReconcileInterval.Annotations = map[string]string{ReconcileIntervalAnnotation: "five minutes"}
+
+	validReconcileFreezeUntil := valid.DeepCopy()
+	validReconcileFreezeUntil.Annotations = map[string]string{ReconcileFreezeUntilAnnotation: "2020-01-01T00:00:00Z"}
+
+	invalidReconcileFreezeUntil := valid.DeepCopy()
+	invalidReconcileFreezeUntil.Annotations = map[string]string{ReconcileFreezeUntilAnnotation: "tomorrow"}
+
+	validInfraAPIVersion := valid.DeepCopy()
+	validInfraAPIVersion.Spec.InfrastructureRef.APIVersion = "infrastructure.cluster.x-k8s.io/v1alpha3"
+	validInfraAPIVersion.Annotations = map[string]string{InfraAPIVersionAnnotation: "infrastructure.cluster.x-k8s.io/v1alpha4"}
+
+	invalidInfraAPIVersion := validInfraAPIVersion.DeepCopy()
+	invalidInfraAPIVersion.Annotations = map[string]string{InfraAPIVersionAnnotation: "other.cluster.x-k8s.io/v1alpha4"}
+
+	validInfrastructureReadyTimeout := valid.DeepCopy()
+	validInfrastructureReadyTimeout.Spec.InfrastructureReadyTimeout = &metav1.Duration{Duration: time.Hour}
+
+	invalidInfrastructureReadyTimeout := valid.DeepCopy()
+	invalidInfrastructureReadyTimeout.Spec.InfrastructureReadyTimeout = &metav1.Duration{Duration: -time.Hour}
+
+	validAdoptInfrastructure := valid.DeepCopy()
+	validAdoptInfrastructure.Annotations = map[string]string{AdoptInfrastructureAnnotation: "my-infrastructure"}
+
+	invalidAdoptInfrastructure := valid.DeepCopy()
+	invalidAdoptInfrastructure.Annotations = map[string]string{AdoptInfrastructureAnnotation: "My Infrastructure"}
+
 	tests := []struct {
 		name      string
 		expectErr bool
 		c         *Cluster
 	}{
+		{
+			name:      "should return error when infrastructure ready timeout is invalid",
+			expectErr: true,
+			c:         invalidInfrastructureReadyTimeout,
+		},
+		{
+			name:      "should succeed when infrastructure ready timeout is valid",
+			expectErr: false,
+			c:         validInfrastructureReadyTimeout,
+		},
+		{
+			name:      "should return error when adopt infrastructure annotation is invalid",
+			expectErr: true,
+			c:         invalidAdoptInfrastructure,
+		},
+		{
+			name:      "should succeed when adopt infrastructure annotation is valid",
+			expectErr: false,
+			c:         validAdoptInfrastructure,
+		},
+		{
+			name:      "should return error when infra API version annotation is invalid",
+			expectErr: true,
+			c:         invalidInfraAPIVersion,
+		},
+		{
+			name:      "should succeed when infra API version annotation is valid",
+			expectErr: false,
+			c:         validInfraAPIVersion,
+		},
+		{
+			name:      "should return error when reconcile freeze until annotation is invalid",
+			expectErr: true,
+			c:         invalidReconcileFreezeUntil,
+		},
+		{
+			name:      "should succeed when reconcile freeze until annotation is valid",
+			expectErr: false,
+			c:         validReconcileFreezeUntil,
+		},
+		{
+			name:      "should return error when reconcile interval annotation is invalid",
+			expectErr: true,
+			c:         invalidReconcileInterval,
+		},
+		{
+			name:      "should succeed when reconcile interval annotation is valid",
+			expectErr: false,
+			c:         validReconcileInterval,
+		},
+		{
+			name:      "should return error when delete grace seconds annotation is invalid",
+			expectErr: true,
+			c:         invalidDeleteGraceSeconds,
+		},
+		{
+			name:      "should succeed when delete grace seconds annotation is valid",
+			expectErr: false,
+			c:         validDeleteGraceSeconds,
+		},
 		{
 			name:      "should return error when cluster namespace and infrastructure ref namespace mismatch",
 			expectErr: true,
@@ -26,6 +26,17 @@ const (
 	// external objects(bootstrap and infrastructure providers)
 	ClusterLabelName = "cluster.x-k8s.io/cluster-name"
 
+	// ClusterUIDLabelName is the label set on the descendants owned by a cluster, with the UID of the cluster.
+	// This allows to tell apart the descendants of a cluster from the ones left behind by a previous cluster
+	// with the same name.
+	ClusterUIDLabelName = "cluster.x-k8s.io/cluster-uid"
+
+	// LegacyClusterLabelName is the label set on machines linked to a cluster by tooling
+	// predating the cluster.x-k8s.io API group.
+	//
+	// Deprecated: use ClusterLabelName instead.
+	LegacyClusterLabelName = "cluster.k8s.io/cluster-name"
+
 	// ProviderLabelName is the label set on components in the provider manifest.
 	// This label allows to easily identify all the components belonging to a provider; the clusterctl
 	// tool uses this label for implementing provider's lifecycle operations.
@@ -38,6 +49,81 @@ const (
 	// on the reconciled object.
 	PausedAnnotation = "cluster.x-k8s.io/paused"
 
+	// UnmanagedAnnotation is an annotation that can be applied to a Cluster to make it a pure record, which
+	// Cluster API does not act on: unlike a paused Cluster, which is going to be reconciled once unpaused,
+	// an unmanaged Cluster never gets the ClusterFinalizer, and is neither provisioned nor deleted by Cluster API.
+	UnmanagedAnnotation = "cluster.x-k8s.io/unmanaged"
+
+	// NormalizeLegacyLabelsAnnotation is an annotation that can be applied to a Cluster to opt-in
+	// adding the ClusterLabelName label to descendants only carrying the LegacyClusterLabelName label.
+	NormalizeLegacyLabelsAnnotation = "cluster.x-k8s.io/normalize-legacy-labels"
+
+	// DeleteGraceSecondsAnnotation is an annotation that can be applied to a Cluster to define the grace period,
+	// in seconds, used when deleting the Cluster's descendants; if not set, the default grace period
+	// of each object applies.
+	DeleteGraceSecondsAnnotation = "cluster.x-k8s.io/delete-grace-seconds"
+
+	// ReconcileIntervalAnnotation is an annotation that can be applied to a Cluster to define the maximum interval,
+	// expressed as a duration string e.g. "5m", between two reconciliations of the Cluster, thus forcing a periodic re-sync.
+	ReconcileIntervalAnnotation = "cluster.x-k8s.io/reconcile-interval"
+
+	// ReconcileFreezeUntilAnnotation is an annotation that can be applied to a Cluster to freeze its reconciliation,
+	// e.g. during a maintenance window, until the given RFC3339 timestamp; reconciliation resumes automatically afterwards.
+	ReconcileFreezeUntilAnnotation = "cluster.x-k8s.io/reconcile-freeze-until"
+
+	// ForceReconcileAnnotation is a one-shot annotation that can be applied to a Cluster to force a full reconciliation,
+	// even if the Cluster is frozen, e.g. when debugging a stuck reconciliation; it is removed once the Cluster is reconciled.
+	ForceReconcileAnnotation = "cluster.x-k8s.io/force-reconcile"
+
+	// InfraAPIVersionAnnotation is an annotation that can be applied to a Cluster to override the API version,
+	// e.g. "infrastructure.cluster.x-k8s.io/v1alpha4", used to get its infrastructure object, thus allowing
+	// a controlled migration between the API versions served by an infrastructure provider.
+	InfraAPIVersionAnnotation = "cluster.x-k8s.io/infra-api-version"
+
+	// AdoptInfrastructureAnnotation is an annotation that can be applied to a Cluster without an infrastructure reference
+	// to adopt a pre-existing infrastructure object with the given name, e.g. created manually, in the Cluster namespace;
+	// the object is looked up among the infrastructure kinds satisfying the Cluster API contract.
+	AdoptInfrastructureAnnotation = "cluster.x-k8s.io/adopt-infrastructure"
+
+	// InfrastructureTemplateAnnotation is an annotation that can be applied to a Cluster to name the template, in the
+	// Cluster namespace, its infrastructure object is recreated from if deleted while the Cluster is not, e.g.
+	// "my-cluster-template" for an infrastructure reference of kind "DockerCluster" identifies the DockerClusterTemplate
+	// with that name and the same API version; it is used only if the Cluster controller is configured to do so.
+	InfrastructureTemplateAnnotation = "cluster.x-k8s.io/infrastructure-template"
+
+	// DeletionOrderConfigMapAnnotation is an annotation that can be applied to a Cluster to name a ConfigMap, in the
+	// Cluster namespace, listing under the "order" key the comma or whitespace separated kinds of descendants, e.g.
+	// "MachineDeployment,MachineSet,Machine", in the order they are deleted; kinds not listed are deleted afterwards
+	// in the default order, and control plane Machines are always deleted last.
+	DeletionOrderConfigMapAnnotation = "cluster.x-k8s.io/deletion-order-configmap"
+
+	// ManagedByAnnotation is an annotation that can be applied to infrastructure objects to signify that some
+	// external system is managing them; Cluster API does not take ownership of such objects.
+	ManagedByAnnotation = "cluster.x-k8s.io/managed-by"
+
+	// PropagatedLabelsAnnotation is an annotation set on the descendants of a Cluster, listing the comma separated
+	// keys of the labels propagated from the Cluster; it is used to remove the labels not present anymore on the Cluster.
+	PropagatedLabelsAnnotation = "cluster.x-k8s.io/propagated-labels"
+
+	// SkipRemediationAnnotation is an annotation that can be applied to a Machine to prevent its remediation by
+	// MachineHealthChecks; when set by the Cluster controller, as defined by Cluster.Spec.DisableMachineHealthChecks,
+	// its value is the name of the Cluster, which carries the annotation as well until its Machines are re-enabled.
+	SkipRemediationAnnotation = "cluster.x-k8s.io/skip-remediation"
+
+	// NodeDrainTimeoutInheritedAnnotation is an annotation set by the Cluster controller on the Machines whose NodeDrainTimeout
+	// has been propagated from Cluster.Spec.NodeDrainTimeout, so their NodeDrainTimeout follows the one of the Cluster;
+	// removing the annotation makes the current NodeDrainTimeout of the Machine its own.
+	NodeDrainTimeoutInheritedAnnotation = "cluster.x-k8s.io/node-drain-timeout-inherited"
+
+	// RequeuePhaseAnnotation is an annotation set on a Cluster by the Cluster controller, reporting the name
+	// of the reconcile phase that determined when the Cluster is going to be requeued, if any.
+	RequeuePhaseAnnotation = "cluster.x-k8s.io/requeue-phase"
+
+	// DescendantTopologyAnnotation is an annotation set on a Cluster by the Cluster controller, if requested, with a JSON
+	// summary of the descendants of the Cluster: their number by kind and, possibly truncated, the kind, name and
+	// controller of each of them, so clients can render the descendant tree without listing the descendants.
+	DescendantTopologyAnnotation = "cluster.x-k8s.io/descendant-topology"
+
 	// ClusterSecretType defines the type of secret created by core components
 	ClusterSecretType corev1.SecretType = "cluster.x-k8s.io/secret" //nolint:gosec
 )
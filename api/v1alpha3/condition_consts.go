@@ -56,3 +56,190 @@ const (
 	// NOTE: This reason is used only as a fallback when the infrastructure object is not reporting its own ready condition.
 	WaitingForInfrastructureFallbackReason = "WaitingForInfrastructure"
 )
+
+// Conditions and condition Reasons for the Cluster object
+
+const (
+	// ControlPlaneReadyCondition reports the ready condition from the control plane object defined for this cluster.
+	// This condition is mirrored from the Ready condition in the control plane ref object, and
+	// the absence of this condition might signal problems in the reconcile external loops or the fact that
+	// the control plane provider does not not implements the Ready condition yet.
+	ControlPlaneReadyCondition ConditionType = "ControlPlaneReady"
+
+	// WaitingForControlPlaneFallbackReason (Severity=Info) documents a cluster waiting for the control plane
+	// to be available.
+	// NOTE: This reason is used only as a fallback when the control plane object is not reporting its own ready condition.
+	WaitingForControlPlaneFallbackReason = "WaitingForControlPlane"
+
+	// WaitingForControlPlaneMachinesReason (Severity=Info) documents a cluster without a control plane provider
+	// waiting for its control plane Machines to be ready.
+	WaitingForControlPlaneMachinesReason = "WaitingForControlPlaneMachines"
+
+	// ProviderNotInstalledReason (Severity=Error) documents a cluster whose control plane object can't be read
+	// because the kind it belongs to is not served by the API server, e.g. because the control plane provider
+	// is not installed.
+	ProviderNotInstalledReason = "ProviderNotInstalled"
+)
+
+const (
+	// MirroredConditionMissingReason documents a condition mirrored from an external object that reports conditions,
+	// but not the Ready condition anymore, e.g. because it has been removed by the provider.
+	MirroredConditionMissingReason = "MirroredConditionMissing"
+)
+
+const (
+	// ControlPlaneReachableCondition reports if the API server of the workload cluster can be reached using
+	// the kubeconfig secret generated for the cluster.
+	// NOTE: This condition is set only when the control plane probe is enabled in the Cluster controller.
+	ControlPlaneReachableCondition ConditionType = "ControlPlaneReachable"
+
+	// ControlPlaneUnreachableReason (Severity=Warning) documents a cluster whose control plane endpoint
+	// did not answer the reachability probe.
+	ControlPlaneUnreachableReason = "ControlPlaneUnreachable"
+)
+
+const (
+	// InfrastructureDeletingReason (Severity=Warning) documents a cluster whose infrastructure object is being deleted
+	// while the cluster itself is not.
+	InfrastructureDeletingReason = "InfrastructureDeleting"
+
+	// InfrastructureDeletedReason (Severity=Warning) documents a cluster whose infrastructure object has been deleted
+	// after being provisioned, while the cluster itself is not being deleted.
+	// NOTE: The infrastructure object is recreated only if the cluster names the template to recreate it from,
+	// given that a v1alpha3 cluster does not reference the template its infrastructure object has been created from.
+	InfrastructureDeletedReason = "InfrastructureDeleted"
+
+	// InfrastructureRecreateFailedReason (Severity=Warning) documents a cluster whose deleted infrastructure object
+	// could not be recreated from the template named by the cluster; recreating it is retried after a backoff.
+	InfrastructureRecreateFailedReason = "InfrastructureRecreateFailed"
+
+	// ControlPlaneDeletingReason (Severity=Info) documents a cluster being deleted whose control plane object
+	// has been in deletion for longer than the configured grace period.
+	ControlPlaneDeletingReason = "ControlPlaneDeleting"
+
+	// InfrastructureDeletionPausedReason (Severity=Info) documents a cluster being deleted whose infrastructure object
+	// is paused, e.g. while an operator inspects it; the infrastructure object is not deleted until it is unpaused.
+	InfrastructureDeletionPausedReason = "InfrastructureDeletionPaused"
+)
+
+const (
+	// InfrastructureManagedByCondition reports the infrastructure object of the cluster being managed by an
+	// external controller, as defined by the ManagedByAnnotation, instead of being provisioned through Cluster API.
+	// NOTE: This condition is set only when the infrastructure object is externally managed.
+	InfrastructureManagedByCondition ConditionType = "InfrastructureManagedBy"
+
+	// ExternallyManagedReason (Severity=Info) documents a cluster whose infrastructure object is managed by an
+	// external controller; the condition message names the managing controller.
+	ExternallyManagedReason = "ExternallyManaged"
+)
+
+const (
+	// ControlPlaneEndpointInSyncCondition reports if the control plane endpoint of the cluster is in sync with the
+	// control plane endpoint exposed by the infrastructure object. Once set, the cluster endpoint is never
+	// overwritten, so a False value signals that the infrastructure endpoint changed afterwards.
+	ControlPlaneEndpointInSyncCondition ConditionType = "ControlPlaneEndpointInSync"
+
+	// ControlPlaneEndpointDriftedReason (Severity=Warning) documents a cluster whose control plane endpoint
+	// differs from the one currently exposed by the infrastructure object.
+	ControlPlaneEndpointDriftedReason = "ControlPlaneEndpointDrifted"
+)
+
+const (
+	// ReferencesDefinedCondition reports if the cluster defines enough information to determine its state, i.e.
+	// an infrastructure reference, a control plane reference, or at least a Machine.
+	// NOTE: This condition is set only when such information is missing.
+	ReferencesDefinedCondition ConditionType = "ReferencesDefined"
+
+	// MissingReferencesReason (Severity=Warning) documents a cluster with neither an infrastructure reference
+	// nor a control plane reference, and without Machines; the phase of such cluster is Unknown.
+	MissingReferencesReason = "MissingReferences"
+)
+
+const (
+	// ControlPlaneMachinesManagedCondition reports if all the control plane Machines of a cluster with a control plane
+	// provider are managed by it.
+	// NOTE: This condition is set only when the cluster has a control plane provider.
+	ControlPlaneMachinesManagedCondition ConditionType = "ControlPlaneMachinesManaged"
+
+	// UnmanagedControlPlaneMachinesReason (Severity=Warning) documents a cluster with a control plane provider
+	// having control plane Machines directly owned by the cluster; such Machines are not managed by the control plane
+	// provider, and are not considered descendants of the cluster during deletion.
+	UnmanagedControlPlaneMachinesReason = "UnmanagedControlPlaneMachines"
+)
+
+const (
+	// DistinctReferencesCondition reports if the control plane and the infrastructure references of the cluster
+	// point to distinct objects.
+	// NOTE: This condition is set only when the references point to the same object.
+	DistinctReferencesCondition ConditionType = "DistinctReferences"
+
+	// IdenticalReferencesReason (Severity=Error) documents a cluster whose control plane and infrastructure references
+	// point to the same object.
+	IdenticalReferencesReason = "IdenticalReferences"
+)
+
+const (
+	// ReferencedKindsValidCondition reports if the control plane and the infrastructure references of the cluster
+	// point to instances rather than to templates, a common mistake when defining a cluster.
+	// NOTE: This condition is set only when a reference points to a template.
+	ReferencedKindsValidCondition ConditionType = "ReferencedKindsValid"
+
+	// TemplateReferencedReason (Severity=Error) documents a cluster whose control plane or infrastructure reference
+	// points to a template instead of an instance.
+	TemplateReferencedReason = "TemplateReferenced"
+)
+
+const (
+	// ControlPlaneEndpointServingCondition reports if the control plane endpoint of a cluster is expected to serve
+	// traffic; it turns false as soon as the cluster is being deleted, so external systems, e.g. load balancers
+	// or DNS, can start draining traffic.
+	// NOTE: This condition is set only when the cluster is being deleted.
+	ControlPlaneEndpointServingCondition ConditionType = "ControlPlaneEndpointServing"
+
+	// ControlPlaneEndpointTerminatingReason (Severity=Info) documents a cluster being deleted, whose control plane
+	// endpoint is going to stop serving traffic.
+	ControlPlaneEndpointTerminatingReason = "ControlPlaneEndpointTerminating"
+
+	// ControlPlaneEndpointTerminatedReason (Severity=Info) documents a cluster being deleted, whose control plane
+	// object is gone, so its control plane endpoint is not served anymore.
+	ControlPlaneEndpointTerminatedReason = "ControlPlaneEndpointTerminated"
+)
+
+const (
+	// TimedOutReason (Severity=Warning) documents a cluster whose infrastructure object did not become ready
+	// within the InfrastructureReadyTimeout since the cluster creation.
+	TimedOutReason = "TimedOut"
+)
+
+const (
+	// NamespaceActiveCondition reports if the namespace of a cluster is active; when the namespace is terminating,
+	// the descendants of the cluster are deleted, given that they can't be created or updated anymore, while the
+	// cluster keeps its finalizer until it is deleted itself.
+	// NOTE: This condition is set only when the namespace is terminating.
+	NamespaceActiveCondition ConditionType = "NamespaceActive"
+
+	// NamespaceTerminatingReason (Severity=Warning) documents a cluster whose namespace is terminating.
+	NamespaceTerminatingReason = "NamespaceTerminating"
+)
+
+const (
+	// DescendantsWithinLimitCondition reports if the number of descendants of a cluster is within the configured
+	// warning threshold, helping operators to catch runaway scaling, e.g. of MachineDeployments.
+	// NOTE: This condition is set only when a warning threshold is configured.
+	DescendantsWithinLimitCondition ConditionType = "DescendantsWithinLimit"
+
+	// TooManyDescendantsReason (Severity=Info) documents a cluster whose number of descendants exceeds
+	// the configured warning threshold.
+	TooManyDescendantsReason = "TooManyDescendants"
+)
+
+const (
+	// DescendantsStableCondition reports if the descendants of a cluster being deleted stay deleted, i.e. they are not
+	// recreated as fast as they are deleted, e.g. by a misbehaving controller, preventing the deletion from completing.
+	// NOTE: This condition is set only when a churning descendant is detected.
+	DescendantsStableCondition ConditionType = "DescendantsStable"
+
+	// DescendantChurningReason (Severity=Warning) documents a cluster being deleted with a descendant which has been
+	// deleted and recreated more times than the configured threshold.
+	DescendantChurningReason = "DescendantChurning"
+)
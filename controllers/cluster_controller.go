@@ -18,32 +18,53 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/metrics"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	utilrecord "sigs.k8s.io/cluster-api/util/record"
 	"sigs.k8s.io/cluster-api/util/secret"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -52,13 +73,78 @@ const (
 	// deleteRequeueAfter is how long to wait before checking again to see if the cluster still has children during
 	// deletion.
 	deleteRequeueAfter = 5 * time.Second
+
+	// deletionOrderConfigMapKey is the key of the ConfigMap named by the DeletionOrderConfigMapAnnotation
+	// listing the kinds of descendants in deletion order.
+	deletionOrderConfigMapKey = "order"
+
+	// kubeconfigEndpointRequeueAfter is how long to wait before checking again to see if the control plane endpoint
+	// is set, so the Kubeconfig can be generated.
+	kubeconfigEndpointRequeueAfter = 10 * time.Second
+
+	// eventDeduplicationWindow is the window within which identical events for a Cluster are emitted only once.
+	eventDeduplicationWindow = 5 * time.Minute
+
+	// defaultControlPlaneProbeTimeout is the default timeout used when probing the control plane endpoint.
+	defaultControlPlaneProbeTimeout = 5 * time.Second
+
+	// defaultListDescendantsTimeout is the default timeout for each List call issued when listing
+	// the descendants of a Cluster.
+	defaultListDescendantsTimeout = 30 * time.Second
+
+	// listDescendantsTimeoutRequeueAfter is how long to wait before trying again to list the descendants
+	// of a Cluster after a List call timed out.
+	listDescendantsTimeoutRequeueAfter = 10 * time.Second
+
+	// defaultInfrastructureNotFoundRequeueAfter is the default time to wait before checking again
+	// if the infrastructure object referenced by a Cluster has been created.
+	defaultInfrastructureNotFoundRequeueAfter = 10 * time.Second
+
+	// defaultControlPlaneDeletingGracePeriod is the default time a control plane object must have been in deletion
+	// before the Cluster reports it as deleting.
+	defaultControlPlaneDeletingGracePeriod = 10 * time.Second
+
+	// defaultControlPlaneResyncPeriod is the default time to wait before re-syncing a Cluster
+	// whose control plane is not ready yet.
+	defaultControlPlaneResyncPeriod = 15 * time.Second
+
+	// infrastructureReadyTimedOutRequeueAfter is how long to wait before checking again if the infrastructure object
+	// of a Cluster is ready, once the InfrastructureReadyTimeout has been exceeded.
+	infrastructureReadyTimedOutRequeueAfter = 30 * time.Second
+
+	// defaultDescendantChurnThreshold is the default number of times a descendant with the same kind and name
+	// can be deleted while deleting a Cluster before it is reported as churning.
+	defaultDescendantChurnThreshold = 5
+
+	// defaultInfrastructureNotFoundThreshold is the default number of consecutive reconciliations the infrastructure
+	// object of a provisioned Cluster must be not found for before the infrastructure is reported as deleted.
+	defaultInfrastructureNotFoundThreshold = 1
+
+	// maxDescendantTopologySize is the maximum size, in bytes, of the summary exported in the DescendantTopologyAnnotation,
+	// given that the annotations of an object are limited to 256KiB in total.
+	maxDescendantTopologySize = 16 * 1024
+
+	// infrastructureGroup is the API group of the infrastructure objects which can be adopted by a Cluster.
+	infrastructureGroup = "infrastructure.cluster.x-k8s.io"
+)
+
+var (
+	// defaultClusterSummaryConditions are the conditions summarized into the Cluster Ready condition
+	// when ClusterReconciler.SummaryConditions is not set.
+	defaultClusterSummaryConditions = []clusterv1.ConditionType{
+		clusterv1.ControlPlaneReadyCondition,
+		clusterv1.InfrastructureReadyCondition,
+	}
 )
 
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;patch;delete
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // ClusterReconciler reconciles a Cluster object
@@ -66,27 +152,424 @@ type ClusterReconciler struct {
 	Client client.Client
 	Log    logr.Logger
 
-	scheme          *runtime.Scheme
-	recorder        record.EventRecorder
-	externalTracker external.ObjectTracker
+	// EnableControlPlaneProbe enables probing the workload cluster API server
+	// and reporting the result in the ControlPlaneReachableCondition.
+	EnableControlPlaneProbe bool
+
+	// ControlPlaneProbeTimeout is the timeout used when probing the workload cluster API server.
+	// Defaults to 5 seconds.
+	ControlPlaneProbeTimeout time.Duration
+
+	// SummaryConditions is the list of conditions summarized into the Cluster Ready condition;
+	// the order of the list defines the priority used for computing the summary Reason and Message.
+	// Defaults to ControlPlaneReady and InfrastructureReady.
+	SummaryConditions []clusterv1.ConditionType
+
+	// NegativePolarityConditions is the list of conditions where Status=True signals a problem, e.g. Degraded;
+	// those conditions are inverted when computing the Cluster Ready condition.
+	NegativePolarityConditions []clusterv1.ConditionType
+
+	// MustBeTrueConditions is the list of conditions which must be True for the Cluster to be Ready, e.g. InfrastructureReady;
+	// when one of them is Unknown, it lowers the Cluster Ready condition instead of being outranked by the True conditions.
+	MustBeTrueConditions []clusterv1.ConditionType
+
+	// ConditionsToMirror is the list of condition types of the infrastructure and control plane objects
+	// taken into account when mirroring their state into the InfrastructureReady and ControlPlaneReady conditions.
+	// If empty, the Ready condition of the external objects is mirrored as is.
+	ConditionsToMirror []clusterv1.ConditionType
+
+	// MirroredConditionSeverity maps the severity of the conditions mirrored from the infrastructure and control plane
+	// objects into the Cluster, e.g. to downgrade the errors reported by providers to warnings while the Cluster is
+	// being deleted. If nil, the severity is mirrored as is.
+	MirroredConditionSeverity func(cluster *clusterv1.Cluster, severity clusterv1.ConditionSeverity) clusterv1.ConditionSeverity
+
+	// ExternalGetter is used to retrieve the external objects referenced by a Cluster, once the API version
+	// to use has been resolved, e.g. to the storage version of kinds not registered in the scheme of the manager.
+	// Defaults to external.Get.
+	ExternalGetter ExternalGetter
+
+	// DeleteWorkerMachinesInBulk deletes the worker Machines of a Cluster being deleted with a single DeleteAllOf call
+	// using the cluster label selector, instead of deleting them one by one; control plane Machines are still
+	// deleted individually, and last.
+	// NOTE: Worker Machines are deleted in bulk only if none of them is owned by other objects, e.g. MachineSets,
+	// the Cluster has no MachineSets, and neither DeleteTransformer nor MinDescendantAgeBeforeDeletion are set;
+	// otherwise they are deleted one by one.
+	DeleteWorkerMachinesInBulk bool
+
+	// ParallelMachineDeletion deletes the control plane and the worker Machines of a Cluster being deleted together,
+	// interleaved, instead of deleting the control plane Machines last, e.g. to speed up the teardown of Clusters
+	// whose workloads don't need to be drained.
+	ParallelMachineDeletion bool
+
+	// MinDescendantAgeBeforeDeletion defers the deletion of the descendants of a Cluster being deleted until they are
+	// at least this old, so objects whose creation is still in flight, e.g. Machines being bootstrapped, are not torn
+	// down right away; if zero, descendants are deleted regardless of their age.
+	MinDescendantAgeBeforeDeletion time.Duration
+
+	// ValidateReferencedKinds enables reporting, in the ReferencedKindsValidCondition, Clusters whose control plane
+	// or infrastructure reference points to a template, i.e. to a kind ending in "Template", instead of an instance.
+	ValidateReferencedKinds bool
+
+	// ClearFailuresOnRecovery clears the FailureReason and the FailureMessage of a Cluster once the failure fields
+	// of the external object they have been detected from are cleared, so the Cluster phase moves away from Failed.
+	// By default, failures are terminal for the Cluster.
+	ClearFailuresOnRecovery bool
+
+	// FilterDescendantsByClusterUID ignores the descendants of a Cluster labeled with the UID of another Cluster,
+	// e.g. left behind by a previous Cluster with the same name, so they are neither adopted nor deleted.
+	// NOTE: The descendants owned by a Cluster are always labeled with its UID.
+	FilterDescendantsByClusterUID bool
+
+	// PropagateLabels propagates the labels of a Cluster to its descendants, and removes the propagated labels
+	// from the descendants once they are removed from the Cluster.
+	// NOTE: Labels in the cluster.x-k8s.io domain, and labels already set on a descendant, are not propagated.
+	PropagateLabels bool
+
+	// ExportKubeconfigConfigMap exports the non-secret parts of the kubeconfig of a Cluster, i.e. the server URL and
+	// the cluster name, to a ConfigMap owned by the Cluster, e.g. for GitOps flows; credentials are never exported.
+	ExportKubeconfigConfigMap bool
+
+	// DeleteClusterSecrets deletes the Secrets labeled with the cluster name and owned by a Cluster, e.g. the
+	// kubeconfig and certificates Secrets, as a last step of the Cluster deletion.
+	DeleteClusterSecrets bool
+
+	// SecretNamespaces are additional namespaces, other than the Cluster namespace, where the Secrets labeled
+	// with the cluster name, e.g. kubeconfig or provider Secrets, are deleted as a last step of the Cluster deletion;
+	// Secrets labeled with the UID of a different Cluster are preserved.
+	SecretNamespaces []string
+
+	// ListDescendantsTimeout is the timeout for each List call issued when listing the descendants of a Cluster.
+	// Defaults to 30 seconds.
+	ListDescendantsTimeout time.Duration
+
+	// InfrastructureNotFoundRequeueAfter is how long to wait before checking again if the infrastructure object
+	// referenced by a Cluster has been created by its controller.
+	// Defaults to 10 seconds.
+	InfrastructureNotFoundRequeueAfter time.Duration
+
+	// ControlPlaneDeletingGracePeriod is how long the control plane object of a Cluster being deleted must have been
+	// in deletion before its state is mirrored into the ControlPlaneReadyCondition, falling back to ControlPlaneDeleting;
+	// this avoids surfacing the conditions of a control plane object which is just transitioning to deletion.
+	// Defaults to 10 seconds.
+	ControlPlaneDeletingGracePeriod time.Duration
+
+	// ControlPlaneResyncPeriod is how long to wait before re-syncing a Cluster with a control plane provider while
+	// its ControlPlaneReadyCondition is not true, so the mirrored conditions are kept fresh even if changes
+	// of the control plane object, e.g. a control plane flapping between reconciles, are missed.
+	// Defaults to 15 seconds.
+	ControlPlaneResyncPeriod time.Duration
+
+	// ResetControlPlaneInitialized resets Status.ControlPlaneInitialized to false for a Cluster without a control plane
+	// provider when no control plane Machine with a NodeRef exists anymore, e.g. during a full rebuild of the control plane.
+	// By default, ControlPlaneInitialized is never reset once set.
+	ResetControlPlaneInitialized bool
+
+	// ExtraReconcilePhases are additional reconciliation phases, e.g. provided by downstream integrations,
+	// called after the built-in ones; the Cluster is requeued according to the soonest requeue of all the phases.
+	ExtraReconcilePhases []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error)
+
+	// MaxDescendantsWarnThreshold is the number of descendants of a Cluster above which a Warning event is emitted
+	// and the DescendantsWithinLimitCondition is set to false, e.g. to catch runaway scaling; if zero, no check applies.
+	MaxDescendantsWarnThreshold int
+
+	// MaxRequeueAfter caps the RequeueAfter of the result of a Cluster reconciliation, so that phases asking to requeue
+	// far in the future, e.g. waiting for a long timeout, don't delay other checks; if zero, no cap applies.
+	MaxRequeueAfter time.Duration
+
+	// PhaseFromConditions derives the phase of a Cluster strictly from its Ready condition, using a deterministic
+	// mapping of the condition status, reason and severity, instead of inferring it from multiple signals.
+	PhaseFromConditions bool
+
+	// BootstrapConfigKinds is the list of bootstrap config kinds whose objects, when labeled with the cluster name,
+	// are counted as descendants of a Cluster, so that the Cluster finalizer is not removed until they are gone.
+	// By default, bootstrap configs are not taken into account.
+	BootstrapConfigKinds []schema.GroupVersionKind
+
+	// DeleteTransformer is called on each owned descendant of a Cluster being deleted immediately before deleting it,
+	// e.g. to add annotations or labels triggering provider-side actions; changes are patched before the deletion.
+	DeleteTransformer func(runtime.Object)
+
+	// LegacyFinalizers are finalizers set on Clusters by other versions of Cluster API, e.g. while two controller
+	// versions are running during an upgrade; they are replaced by the ClusterFinalizer, so this controller owns
+	// the deletion of the Clusters.
+	LegacyFinalizers []string
+
+	// ReadOnly makes the reconciler compute the conditions, the phase and the status of the Clusters without issuing
+	// any write, e.g. patches, deletions or finalizer changes, allowing to safely observe Clusters during migrations.
+	// NOTE: Events are still recorded.
+	ReadOnly bool
+
+	// Tracer is used to trace each reconciliation of a Cluster and each of its phases, e.g. by adapting
+	// an OpenTelemetry tracer, so the latency of the reconciliations can be broken down.
+	// Defaults to a no-op tracer.
+	Tracer Tracer
+
+	// ExportDescendantTopology exports a summary of the descendants of each Cluster in the DescendantTopologyAnnotation,
+	// e.g. for clusterctl to render the descendant tree without listing the descendants.
+	ExportDescendantTopology bool
+
+	// Clock is used to read the current time, e.g. for evaluating timeouts and grace periods.
+	// Defaults to the system clock.
+	Clock Clock
+
+	// DescendantChurnThreshold is the number of times a descendant with the same kind and name can be deleted
+	// while deleting a Cluster, i.e. recreated after each deletion, before it is reported as churning.
+	// Defaults to 5.
+	DescendantChurnThreshold int
+
+	// InfrastructureNotFoundThreshold is the number of consecutive reconciliations the infrastructure object of
+	// a provisioned Cluster must be not found for before the InfrastructureReadyCondition is set to false, so that
+	// infrastructure objects briefly missing, e.g. while being recreated by their controller, don't flap the condition.
+	// Defaults to 1, i.e. the infrastructure is reported as deleted as soon as its object is not found.
+	InfrastructureNotFoundThreshold int
+
+	// RecreateDeletedInfrastructure recreates the infrastructure object of a provisioned Cluster when it is deleted
+	// while the Cluster is not, cloning the template named by the InfrastructureTemplateAnnotation of the Cluster;
+	// Clusters without the annotation only report the infrastructure as deleted, given that a v1alpha3 Cluster does
+	// not reference the template its infrastructure object has been created from. Failed attempts are retried
+	// after InfrastructureNotFoundRequeueAfter.
+	RecreateDeletedInfrastructure bool
+
+	scheme                   *runtime.Scheme
+	recorder                 record.EventRecorder
+	externalTracker          external.ObjectTracker
+	descendantKinds          []descendantKind
+	descendantDeletions      descendantDeletions
+	infrastructureNotFound   infrastructureNotFound
+	controlPlaneProbeClients controlPlaneProbeClients
+}
+
+// descendantDeletions counts the deletions of the descendants of the Clusters being deleted, by Cluster and by
+// descendant kind and name, so descendants recreated as fast as they are deleted can be detected across reconciles.
+type descendantDeletions struct {
+	lock   sync.Mutex
+	counts map[types.UID]map[string]descendantDeletionCount
+}
+
+// descendantDeletionCount is the number of deletions of a descendant, and the UID of the last deleted instance.
+type descendantDeletionCount struct {
+	uid   types.UID
+	count int
+}
+
+// record records the deletion of the given instance of a descendant of a Cluster, returning how many instances of
+// the descendant have been deleted so far; deleting the same instance multiple times counts once.
+func (d *descendantDeletions) record(cluster types.UID, descendant string, uid types.UID) int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.counts == nil {
+		d.counts = map[types.UID]map[string]descendantDeletionCount{}
+	}
+	if d.counts[cluster] == nil {
+		d.counts[cluster] = map[string]descendantDeletionCount{}
+	}
+	c := d.counts[cluster][descendant]
+	if c.count == 0 || c.uid != uid {
+		c = descendantDeletionCount{uid: uid, count: c.count + 1}
+		d.counts[cluster][descendant] = c
+	}
+	return c.count
+}
+
+// forget drops the deletions recorded for a Cluster.
+func (d *descendantDeletions) forget(cluster types.UID) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.counts, cluster)
+}
+
+// infrastructureNotFound counts, by Cluster, the consecutive reconciliations the infrastructure object
+// of a provisioned Cluster has not been found for.
+type infrastructureNotFound struct {
+	lock   sync.Mutex
+	counts map[types.UID]int
+}
+
+// record records the infrastructure object of a Cluster not being found, returning for how many consecutive
+// reconciliations it has not been found so far.
+func (n *infrastructureNotFound) record(cluster types.UID) int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.counts == nil {
+		n.counts = map[types.UID]int{}
+	}
+	n.counts[cluster]++
+	return n.counts[cluster]
+}
+
+// forget drops the count recorded for a Cluster, e.g. once its infrastructure object has been found.
+func (n *infrastructureNotFound) forget(cluster types.UID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.counts, cluster)
+}
+
+// DescendantListFunc lists the objects of a custom kind of Cluster descendants matching the given options.
+type DescendantListFunc func(ctx context.Context, c client.Client, opts ...client.ListOption) (runtime.Object, error)
+
+// descendantKind is a custom kind of Cluster descendants registered with RegisterDescendantKind.
+type descendantKind struct {
+	gvk    schema.GroupVersionKind
+	listFn DescendantListFunc
+}
+
+// RegisterDescendantKind registers a custom kind of Cluster descendants, e.g. introduced by a provider, to be counted
+// and deleted along with the built-in ones, before Machines; objects of the given kind are listed using listFn,
+// or as unstructured objects if listFn is nil.
+// NOTE: Custom kinds must be registered before calling SetupWithManager.
+func (r *ClusterReconciler) RegisterDescendantKind(gvk schema.GroupVersionKind, listFn DescendantListFunc) {
+	if listFn == nil {
+		listFn = func(ctx context.Context, c client.Client, opts ...client.ListOption) (runtime.Object, error) {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := c.List(ctx, list, opts...); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+	}
+	r.descendantKinds = append(r.descendantKinds, descendantKind{gvk: gvk, listFn: listFn})
+}
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Tracer starts tracing spans, e.g. backed by OpenTelemetry.
+type Tracer interface {
+	// Start starts a span with the given name and attributes, returning a context carrying the span.
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+// Span is a tracing span started by a Tracer.
+type Span interface {
+	// End ends the span.
+	End()
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ map[string]string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End() {}
+
+// ExternalGetter retrieves an external object referenced by a Cluster.
+type ExternalGetter interface {
+	Get(ctx context.Context, c client.Client, ref *corev1.ObjectReference, namespace string) (*unstructured.Unstructured, error)
+}
+
+// ExternalGetterFunc is an adapter to allow the use of ordinary functions as ExternalGetter.
+type ExternalGetterFunc func(ctx context.Context, c client.Client, ref *corev1.ObjectReference, namespace string) (*unstructured.Unstructured, error)
+
+// Get calls f(ctx, c, ref, namespace).
+func (f ExternalGetterFunc) Get(ctx context.Context, c client.Client, ref *corev1.ObjectReference, namespace string) (*unstructured.Unstructured, error) {
+	return f(ctx, c, ref, namespace)
+}
+
+// readOnlyClient is a client dropping all the writes, used by a ClusterReconciler in ReadOnly mode.
+type readOnlyClient struct {
+	client.Client
+	log logr.Logger
+}
+
+func (c readOnlyClient) Create(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+	return c.drop("create", obj)
+}
+
+func (c readOnlyClient) Update(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+	return c.drop("update", obj)
+}
+
+func (c readOnlyClient) Patch(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return c.drop("patch", obj)
+}
+
+func (c readOnlyClient) Delete(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+	return c.drop("delete", obj)
+}
+
+func (c readOnlyClient) DeleteAllOf(_ context.Context, obj runtime.Object, _ ...client.DeleteAllOfOption) error {
+	return c.drop("deleteAllOf", obj)
+}
+
+func (c readOnlyClient) Status() client.StatusWriter {
+	return readOnlyStatusWriter{c}
+}
+
+// drop logs a write dropped by the readOnlyClient.
+func (c readOnlyClient) drop(verb string, obj runtime.Object) error {
+	name := ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		name = accessor.GetName()
+	}
+	c.log.V(4).Info("Skipping write in read-only mode", "verb", verb, "kind", fmt.Sprintf("%T", obj), "name", name)
+	return nil
+}
+
+// readOnlyStatusWriter is a status writer dropping all the writes, used by the readOnlyClient.
+type readOnlyStatusWriter struct {
+	c readOnlyClient
+}
+
+func (w readOnlyStatusWriter) Update(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+	return w.c.drop("update status", obj)
+}
+
+func (w readOnlyStatusWriter) Patch(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return w.c.drop("patch status", obj)
 }
 
 func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
-	controller, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Cluster{}).
 		Watches(
 			&source.Kind{Type: &clusterv1.Machine{}},
 			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.controlPlaneMachineToCluster)},
 		).
+		Watches(
+			&source.Kind{Type: &apiextensionsv1.CustomResourceDefinition{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.crdToClusters)},
+		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPaused(r.Log)).
-		Build(r)
+		WithEventFilter(ignoreClusterStatusUpdates())
 
+	if feature.Gates.Enabled(feature.MachinePool) {
+		b = b.Watches(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.controlPlaneMachinePoolToCluster)},
+		)
+	}
+
+	controller, err := b.Build(r)
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
-	r.recorder = mgr.GetEventRecorderFor("cluster-controller")
+	err = controller.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.kubeconfigSecretToCluster)},
+		kubeconfigSecrets(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to add Watch for kubeconfig Secrets to controller manager")
+	}
+
+	if r.ReadOnly {
+		r.Client = readOnlyClient{Client: r.Client, log: r.Log}
+	}
+	r.recorder = utilrecord.NewDeduplicatingRecorder(mgr.GetEventRecorderFor("cluster-controller"), eventDeduplicationWindow)
 	r.scheme = mgr.GetScheme()
 	r.externalTracker = external.ObjectTracker{
 		Controller: controller,
@@ -95,7 +578,12 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager, options controlle
 }
 
 func (r *ClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
-	ctx := context.Background()
+	ctx, span := r.tracer().Start(context.Background(), "Reconcile", map[string]string{
+		"cluster":   req.Name,
+		"namespace": req.Namespace,
+	})
+	defer span.End()
+
 	logger := r.Log.WithValues("cluster", req.Name, "namespace", req.Namespace)
 
 	// Fetch the Cluster instance.
@@ -111,25 +599,59 @@ func (r *ClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr e
 		return ctrl.Result{}, err
 	}
 
+	// Return early if the Cluster is unmanaged; unlike a paused Cluster, an unmanaged Cluster must not carry
+	// the finalizer, given that the deletion of its descendants is not going to be handled.
+	if annotations.IsUnmanaged(cluster) {
+		logger.V(4).Info("Skipping reconciliation of unmanaged object")
+		return ctrl.Result{}, r.removeUnmanagedFinalizer(ctx, cluster)
+	}
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, cluster) {
 		logger.Info("Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the Cluster is frozen, requeuing so reconciliation resumes when the freeze ends,
+	// unless a reconciliation is forced.
+	_, forced := cluster.Annotations[clusterv1.ForceReconcileAnnotation]
+	if !forced {
+		frozenFor, err := reconcileFrozenFor(cluster, r.now())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if frozenFor > 0 {
+			logger.Info("Reconciliation is frozen for this object", "until", cluster.Annotations[clusterv1.ReconcileFreezeUntilAnnotation])
+			return ctrl.Result{RequeueAfter: frozenFor}, nil
+		}
+	}
+
 	// Initialize the patch helper.
 	patchHelper, err := patch.NewHelper(cluster, r.Client)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Remove the one-shot force reconcile annotation; the removal is persisted by the patch below,
+	// thus avoiding to force reconciliations in a loop.
+	if forced {
+		logger.Info("Forcing reconciliation of this object")
+		delete(cluster.Annotations, clusterv1.ForceReconcileAnnotation)
+	}
+
+	// Migrate the finalizers set by other versions of Cluster API, if any.
+	r.migrateLegacyFinalizers(cluster)
+
 	defer func() {
 		// Always reconcile the Status.Phase field.
 		r.reconcilePhase(ctx, cluster)
 		r.reconcileMetrics(ctx, cluster)
 
+		// Always record the outcome of the reconciliation.
+		recordReconcileOutcome(cluster, reterr, r.now())
+
 		// Always attempt to Patch the Cluster object and status after each reconciliation.
-		if err := patchHelper.Patch(ctx, cluster); err != nil {
+		if err := r.patchCluster(ctx, patchHelper, cluster); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, err})
 		}
 	}()
@@ -139,34 +661,356 @@ func (r *ClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr e
 		return r.reconcileDelete(ctx, cluster)
 	}
 
+	// Handle the Cluster as being deleted if its namespace is terminating, given that its descendants
+	// can't be created or updated anymore.
+	// NOTE: The Cluster keeps its finalizer until the Cluster itself is deleted.
+	if r.namespaceTerminating(ctx, cluster) {
+		logger.Info("Namespace is terminating, handling the Cluster as being deleted")
+		conditions.MarkFalse(cluster, clusterv1.NamespaceActiveCondition, clusterv1.NamespaceTerminatingReason, clusterv1.ConditionSeverityWarning,
+			"Namespace %q is terminating", cluster.Namespace)
+		return r.reconcileDelete(ctx, cluster)
+	}
+
 	// Handle normal reconciliation loop.
 	return r.reconcile(ctx, cluster)
 }
 
+// removeUnmanagedFinalizer removes the ClusterFinalizer from an unmanaged Cluster, e.g. previously managed,
+// so the Cluster can be deleted without Cluster API acting on it.
+func (r *ClusterReconciler) removeUnmanagedFinalizer(ctx context.Context, cluster *clusterv1.Cluster) error {
+	if !sets.NewString(cluster.Finalizers...).Has(clusterv1.ClusterFinalizer) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return err
+	}
+	controllerutil.RemoveFinalizer(cluster, clusterv1.ClusterFinalizer)
+	if err := patchHelper.Patch(ctx, cluster); err != nil {
+		return errors.Wrapf(err, "failed to remove finalizer from unmanaged Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+	return nil
+}
+
+// migrateLegacyFinalizers replaces the LegacyFinalizers on a Cluster with the ClusterFinalizer.
+// NOTE: Finalizers can't be added to a Cluster being deleted, so in this case the LegacyFinalizers
+// are removed only if the Cluster already carries the ClusterFinalizer.
+func (r *ClusterReconciler) migrateLegacyFinalizers(cluster *clusterv1.Cluster) {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	finalizers := sets.NewString(cluster.Finalizers...)
+	deleting := !cluster.ObjectMeta.DeletionTimestamp.IsZero()
+	for _, legacy := range r.LegacyFinalizers {
+		if legacy == clusterv1.ClusterFinalizer || !finalizers.Has(legacy) {
+			continue
+		}
+		if deleting && !finalizers.Has(clusterv1.ClusterFinalizer) {
+			logger.Info("Cluster being deleted carries a legacy finalizer, leaving it to its owner", "finalizer", legacy)
+			continue
+		}
+
+		logger.Info("Migrating legacy finalizer", "finalizer", legacy)
+		controllerutil.RemoveFinalizer(cluster, legacy)
+		controllerutil.AddFinalizer(cluster, clusterv1.ClusterFinalizer)
+	}
+}
+
+// namespaceTerminating returns true if the namespace of the Cluster is terminating.
+func (r *ClusterReconciler) namespaceTerminating(ctx context.Context, cluster *clusterv1.Cluster) bool {
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster.Namespace}, namespace); err != nil {
+		// If the namespace can't be retrieved, do not make assumptions on its state.
+		if !apierrors.IsNotFound(err) {
+			r.Log.Error(err, "Failed to get namespace", "cluster", cluster.Name, "namespace", cluster.Namespace)
+		}
+		return false
+	}
+	return namespace.Status.Phase == corev1.NamespaceTerminating
+}
+
+// reconcileFrozenFor returns how long the reconciliation of the Cluster is still frozen for, according to the
+// ReconcileFreezeUntilAnnotation, if any, as of now.
+func reconcileFrozenFor(cluster *clusterv1.Cluster, now time.Time) (time.Duration, error) {
+	value, ok := cluster.Annotations[clusterv1.ReconcileFreezeUntilAnnotation]
+	if !ok {
+		return 0, nil
+	}
+
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q for annotation %q on Cluster %q in namespace %q: must be an RFC3339 timestamp",
+			value, clusterv1.ReconcileFreezeUntilAnnotation, cluster.Name, cluster.Namespace)
+	}
+
+	if remaining := until.Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+func (r *ClusterReconciler) patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clusterv1.Cluster) error {
+	// Always update the readyCondition by summarizing the state of other conditions.
+	r.setReadyCondition(cluster)
+	sortConditions(cluster, r.summaryConditions())
+	return patchHelper.Patch(ctx, cluster)
+}
+
+// setReadyCondition sets the Ready condition of a Cluster by summarizing the state of the other conditions.
+func (r *ClusterReconciler) setReadyCondition(cluster *clusterv1.Cluster) {
+	conditions.SetSummary(cluster,
+		conditions.WithConditions(r.summaryConditions()...),
+		conditions.WithNegativePolarityConditions(r.NegativePolarityConditions...),
+		conditions.WithMustBeTrueConditions(r.MustBeTrueConditions...),
+	)
+}
+
+// sortConditions sorts the conditions of a Cluster in a canonical order, for convenience of the consumer, i.e. kubectl:
+// the Ready condition goes first, followed by the given conditions in order, and then by all the other conditions
+// sorted by Type.
+func sortConditions(cluster *clusterv1.Cluster, order []clusterv1.ConditionType) {
+	rank := func(t clusterv1.ConditionType) int {
+		if t == clusterv1.ReadyCondition {
+			return 0
+		}
+		for i := range order {
+			if order[i] == t {
+				return i + 1
+			}
+		}
+		return len(order) + 1
+	}
+
+	sorted := cluster.GetConditions()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank(sorted[i].Type), rank(sorted[j].Type)
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+	cluster.SetConditions(sorted)
+}
+
+// recordReconcileOutcome sets the LastReconcileTime and keeps track of consecutive reconcile errors
+// in the Cluster status.
+func recordReconcileOutcome(cluster *clusterv1.Cluster, reconcileErr error, now time.Time) {
+	lastReconcileTime := metav1.NewTime(now)
+	cluster.Status.LastReconcileTime = &lastReconcileTime
+	if reconcileErr != nil {
+		cluster.Status.ConsecutiveReconcileErrors++
+		return
+	}
+	cluster.Status.ConsecutiveReconcileErrors = 0
+}
+
+// mirrorGetter returns the Getter used for mirroring the conditions of an external object into the Cluster.
+func (r *ClusterReconciler) mirrorGetter(obj *unstructured.Unstructured) conditions.Getter {
+	getter := conditions.UnstructuredGetter(obj)
+	if len(r.ConditionsToMirror) == 0 {
+		return getter
+	}
+	return conditions.FilteredGetter(getter, r.ConditionsToMirror...)
+}
+
+// mirrorReadyCondition mirrors the Ready condition of an external object into the given Cluster condition.
+// The fallback value is used only if the external object does not report any condition; if the external object
+// reports conditions but the Ready condition is missing, the Cluster condition is set to Unknown instead of
+// retaining the last mirrored value.
+func (r *ClusterReconciler) mirrorReadyCondition(cluster *clusterv1.Cluster, t clusterv1.ConditionType, obj *unstructured.Unstructured, fallbackValue bool, fallbackReason string) {
+	getter := r.mirrorGetter(obj)
+	if len(getter.GetConditions()) > 0 && !conditions.Has(getter, clusterv1.ReadyCondition) {
+		conditions.MarkUnknown(cluster, t, clusterv1.MirroredConditionMissingReason,
+			"%s %q does not report the %s condition", obj.GetKind(), obj.GetName(), clusterv1.ReadyCondition)
+		return
+	}
+
+	options := []conditions.MirrorOptions{
+		conditions.WithFallbackValue(fallbackValue, fallbackReason, clusterv1.ConditionSeverityInfo, ""),
+	}
+	if r.MirroredConditionSeverity != nil {
+		options = append(options, conditions.WithSeverityMapping(func(severity clusterv1.ConditionSeverity) clusterv1.ConditionSeverity {
+			return r.MirroredConditionSeverity(cluster, severity)
+		}))
+	}
+	conditions.SetMirror(cluster, t, getter, options...)
+}
+
+// now returns the current time according to the Clock of the reconciler.
+func (r *ClusterReconciler) now() time.Time {
+	if r.Clock == nil {
+		return realClock{}.Now()
+	}
+	return r.Clock.Now()
+}
+
+// tracer returns the Tracer of the reconciler, defaulting to a no-op tracer.
+func (r *ClusterReconciler) tracer() Tracer {
+	if r.Tracer == nil {
+		return noopTracer{}
+	}
+	return r.Tracer
+}
+
+// startPhaseSpan starts a tracing span for a reconcile phase of a Cluster.
+func (r *ClusterReconciler) startPhaseSpan(ctx context.Context, cluster *clusterv1.Cluster, phase string) (context.Context, Span) {
+	return r.tracer().Start(ctx, phase, map[string]string{
+		"cluster":   cluster.Name,
+		"namespace": cluster.Namespace,
+		"phase":     phase,
+	})
+}
+
+// externalGetter returns the ExternalGetter to be used for retrieving external objects.
+func (r *ClusterReconciler) externalGetter() ExternalGetter {
+	if r.ExternalGetter == nil {
+		return ExternalGetterFunc(external.Get)
+	}
+	return r.ExternalGetter
+}
+
+// infrastructureNotFoundRequeueAfter returns how long to wait before checking again if the infrastructure object exists.
+func (r *ClusterReconciler) infrastructureNotFoundRequeueAfter() time.Duration {
+	if r.InfrastructureNotFoundRequeueAfter == 0 {
+		return defaultInfrastructureNotFoundRequeueAfter
+	}
+	return r.InfrastructureNotFoundRequeueAfter
+}
+
+// infrastructureNotFoundThreshold returns for how many consecutive reconciliations the infrastructure object
+// of a provisioned Cluster must be not found before the infrastructure is reported as deleted.
+func (r *ClusterReconciler) infrastructureNotFoundThreshold() int {
+	if r.InfrastructureNotFoundThreshold == 0 {
+		return defaultInfrastructureNotFoundThreshold
+	}
+	return r.InfrastructureNotFoundThreshold
+}
+
+// descendantChurnThreshold returns how many times a descendant can be deleted before it is reported as churning.
+func (r *ClusterReconciler) descendantChurnThreshold() int {
+	if r.DescendantChurnThreshold == 0 {
+		return defaultDescendantChurnThreshold
+	}
+	return r.DescendantChurnThreshold
+}
+
+// listDescendantsTimeout returns the timeout for each List call issued when listing the descendants of a Cluster.
+func (r *ClusterReconciler) listDescendantsTimeout() time.Duration {
+	if r.ListDescendantsTimeout == 0 {
+		return defaultListDescendantsTimeout
+	}
+	return r.ListDescendantsTimeout
+}
+
+// controlPlaneDeletingGracePeriod returns how long a control plane object must have been in deletion
+// before the Cluster reports it as deleting.
+func (r *ClusterReconciler) controlPlaneDeletingGracePeriod() time.Duration {
+	if r.ControlPlaneDeletingGracePeriod == 0 {
+		return defaultControlPlaneDeletingGracePeriod
+	}
+	return r.ControlPlaneDeletingGracePeriod
+}
+
+// controlPlaneResyncPeriod returns how long to wait before re-syncing a Cluster whose control plane is not ready.
+func (r *ClusterReconciler) controlPlaneResyncPeriod() time.Duration {
+	if r.ControlPlaneResyncPeriod == 0 {
+		return defaultControlPlaneResyncPeriod
+	}
+	return r.ControlPlaneResyncPeriod
+}
+
+// summaryConditions returns the list of conditions to be summarized into the Cluster Ready condition.
+func (r *ClusterReconciler) summaryConditions() []clusterv1.ConditionType {
+	if len(r.SummaryConditions) == 0 {
+		return defaultClusterSummaryConditions
+	}
+	return r.SummaryConditions
+}
+
 // reconcile handles cluster reconciliation.
 func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
 
 	// If object doesn't have a finalizer, add one.
+	finalizers := len(cluster.Finalizers)
 	controllerutil.AddFinalizer(cluster, clusterv1.ClusterFinalizer)
+	if len(cluster.Finalizers) > finalizers {
+		metrics.ClusterFinalizerAdded.Inc()
+	}
 
-	// Call the inner reconciliation methods.
-	reconciliationErrors := []error{
-		r.reconcileInfrastructure(ctx, cluster),
-		r.reconcileControlPlane(ctx, cluster),
-		r.reconcileKubeconfig(ctx, cluster),
-		r.reconcileControlPlaneInitialized(ctx, cluster),
+	// The objects referenced by the Cluster are read once, after the infrastructure has been adopted,
+	// and the resulting snapshot is shared by the infrastructure and control plane phases.
+	readiness := &clusterReadiness{}
+
+	// The descendants of the Cluster are listed once, after the legacy labels have been normalized, and the resulting
+	// list is shared by the phases acting on them, which update it in place when patching a descendant; those phases
+	// are skipped if the descendants can't be listed.
+	var descendants *clusterDescendants
+	withDescendants := func(fn func(context.Context, *clusterv1.Cluster, *clusterDescendants) error) func(context.Context, *clusterv1.Cluster) error {
+		return func(ctx context.Context, cluster *clusterv1.Cluster) error {
+			if descendants == nil {
+				return nil
+			}
+			return fn(ctx, cluster, descendants)
+		}
+	}
+
+	// Call the inner reconciliation methods, tracing each of them.
+	phases := []struct {
+		name string
+		fn   func(context.Context, *clusterv1.Cluster) error
+	}{
+		{"reconcileReferences", r.reconcileReferences},
+		{"reconcileReferencedKinds", r.reconcileReferencedKinds},
+		{"reconcileClusterLabel", r.reconcileClusterLabel},
+		{"reconcileLegacyLabels", r.reconcileLegacyLabels},
+		{"listDescendants", func(ctx context.Context, cluster *clusterv1.Cluster) error {
+			listed, err := r.listDescendants(ctx, cluster)
+			if err != nil {
+				return err
+			}
+			descendants = &listed
+			return nil
+		}},
+		{"reconcileDescendantOwnerReferences", withDescendants(r.reconcileDescendantOwnerReferences)},
+		{"reconcileDescendantLabels", withDescendants(r.reconcileDescendantLabels)},
+		{"reconcileDescendantClusterUIDLabel", withDescendants(r.reconcileDescendantClusterUIDLabel)},
+		{"reconcileDescendantsLimit", withDescendants(r.reconcileDescendantsLimit)},
+		{"reconcileDescendantTopology", withDescendants(r.reconcileDescendantTopology)},
+		{"reconcileNodeDrainTimeout", withDescendants(r.reconcileNodeDrainTimeout)},
+		{"reconcileMachineHealthChecksDisabled", withDescendants(r.reconcileMachineHealthChecksDisabled)},
+		{"adoptInfrastructure", r.adoptInfrastructure},
+		{"computeReadiness", func(ctx context.Context, cluster *clusterv1.Cluster) error {
+			readiness = r.computeReadiness(ctx, cluster)
+			return nil
+		}},
+		{"reconcileInfrastructure", func(ctx context.Context, cluster *clusterv1.Cluster) error {
+			return r.reconcileInfrastructureReadiness(ctx, cluster, readiness)
+		}},
+		{"reconcileControlPlane", func(ctx context.Context, cluster *clusterv1.Cluster) error {
+			return r.reconcileControlPlaneReadiness(ctx, cluster, readiness)
+		}},
+		{"reconcileUnmanagedControlPlaneMachines", r.reconcileUnmanagedControlPlaneMachines},
+		{"reconcileKubeconfig", r.reconcileKubeconfig},
+		{"reconcileControlPlaneInitialized", r.reconcileControlPlaneInitialized},
+		{"reconcileControlPlaneReachable", r.reconcileControlPlaneReachable},
 	}
 
 	// Parse the errors, making sure we record if there is a RequeueAfterError.
-	res := ctrl.Result{}
+	res := phaseResult{}
 	errs := []error{}
-	for _, err := range reconciliationErrors {
+	for _, phase := range phases {
+		phaseCtx, span := r.startPhaseSpan(ctx, cluster, phase.name)
+		err := phase.fn(phaseCtx, cluster)
+		span.End()
+
 		if requeueErr, ok := errors.Cause(err).(capierrors.HasRequeueAfterError); ok {
 			// Only record and log the first RequeueAfterError.
-			if !res.Requeue {
-				res.Requeue = true
-				res.RequeueAfter = requeueErr.GetRequeueAfter()
+			if !res.result.Requeue {
+				res = phaseResult{
+					phase:  phase.name,
+					result: ctrl.Result{Requeue: true, RequeueAfter: requeueErr.GetRequeueAfter()},
+				}
 				logger.Error(err, "Reconciliation for Cluster asked to requeue")
 			}
 			continue
@@ -174,7 +1018,111 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cl
 
 		errs = append(errs, err)
 	}
-	return res, kerrors.NewAggregate(errs)
+
+	// Call the extra reconciliation phases, if any, honoring the soonest requeue.
+	for i, phase := range r.ExtraReconcilePhases {
+		name := fmt.Sprintf("ExtraReconcilePhases[%d]", i)
+		phaseCtx, span := r.startPhaseSpan(ctx, cluster, name)
+		phaseRes, err := phase(phaseCtx, cluster)
+		span.End()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		res = res.lowestNonZero(phaseResult{phase: name, result: phaseRes})
+	}
+
+	// Re-sync the Cluster while its control plane is transitioning.
+	res = res.apply("controlPlaneResync", r.applyControlPlaneResync(cluster, res.result))
+
+	// Force a periodic re-sync of the Cluster, if requested.
+	intervalRes, err := applyReconcileInterval(cluster, res.result)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	res = res.apply("reconcileInterval", intervalRes)
+
+	// Cap the requeue of the Cluster, if requested.
+	res = res.apply("maxRequeueAfter", r.applyMaxRequeueAfter(res.result))
+
+	// Report which phase is driving the requeue of the Cluster.
+	setRequeuePhase(cluster, res)
+	return res.result, kerrors.NewAggregate(errs)
+}
+
+// phaseResult is the result of a reconcile phase, tagged with the name of the phase.
+type phaseResult struct {
+	phase  string
+	result ctrl.Result
+}
+
+// lowestNonZero returns the phaseResult with the lowest non zero result, preferring the receiver on ties.
+func (p phaseResult) lowestNonZero(other phaseResult) phaseResult {
+	if util.LowestNonZeroResult(p.result, other.result) == p.result {
+		return p
+	}
+	return other
+}
+
+// apply returns the given result tagged with the given phase if it differs from the current one,
+// otherwise the current phaseResult.
+func (p phaseResult) apply(phase string, result ctrl.Result) phaseResult {
+	if result == p.result {
+		return p
+	}
+	return phaseResult{phase: phase, result: result}
+}
+
+// setRequeuePhase records the phase driving the requeue of the Cluster in the RequeuePhaseAnnotation,
+// removing the annotation if the Cluster is not going to be requeued.
+func setRequeuePhase(cluster *clusterv1.Cluster, res phaseResult) {
+	if res.result == (ctrl.Result{}) || res.phase == "" {
+		delete(cluster.Annotations, clusterv1.RequeuePhaseAnnotation)
+		return
+	}
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[clusterv1.RequeuePhaseAnnotation] = res.phase
+}
+
+// applyMaxRequeueAfter caps the RequeueAfter of the given result to the MaxRequeueAfter, if any.
+func (r *ClusterReconciler) applyMaxRequeueAfter(res ctrl.Result) ctrl.Result {
+	if r.MaxRequeueAfter > 0 && res.RequeueAfter > r.MaxRequeueAfter {
+		res.RequeueAfter = r.MaxRequeueAfter
+	}
+	return res
+}
+
+// applyReconcileInterval ensures the Cluster is requeued within the interval defined by the
+// ReconcileIntervalAnnotation, if any.
+func applyReconcileInterval(cluster *clusterv1.Cluster, res ctrl.Result) (ctrl.Result, error) {
+	value, ok := cluster.Annotations[clusterv1.ReconcileIntervalAnnotation]
+	if !ok {
+		return res, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return res, errors.Errorf("invalid value %q for annotation %q on Cluster %q in namespace %q: must be a positive duration",
+			value, clusterv1.ReconcileIntervalAnnotation, cluster.Name, cluster.Namespace)
+	}
+
+	if res.RequeueAfter == 0 || res.RequeueAfter > interval {
+		res.RequeueAfter = interval
+	}
+	return res, nil
+}
+
+// applyControlPlaneResync ensures a Cluster with a control plane provider is requeued within the control plane
+// resync period while its ControlPlaneReadyCondition is not true, so the state of the control plane object
+// is eventually mirrored into the Cluster.
+func (r *ClusterReconciler) applyControlPlaneResync(cluster *clusterv1.Cluster, res ctrl.Result) ctrl.Result {
+	if cluster.Spec.ControlPlaneRef == nil || !conditions.Has(cluster, clusterv1.ControlPlaneReadyCondition) ||
+		conditions.IsTrue(cluster, clusterv1.ControlPlaneReadyCondition) {
+		return res
+	}
+	return util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: r.controlPlaneResyncPeriod()})
 }
 
 func (r *ClusterReconciler) reconcileMetrics(_ context.Context, cluster *clusterv1.Cluster) {
@@ -206,117 +1154,536 @@ func (r *ClusterReconciler) reconcileMetrics(_ context.Context, cluster *cluster
 }
 
 // reconcileDelete handles cluster deletion.
-func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster) (reconcile.Result, error) {
+func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster) (_ reconcile.Result, reterr error) {
 	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
 
-	descendants, err := r.listDescendants(ctx, cluster)
-	if err != nil {
-		logger.Error(err, "Failed to list descendants")
+	// Surface the objects blocking the deletion of the Cluster, if any.
+	defer func() {
+		if capierrors.IsDeletionBlocked(reterr) {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DeletionBlocked", "Cluster deletion is blocked: %v", reterr)
+		}
+	}()
+
+	// Signal that the control plane endpoint is terminating before deleting anything, so external systems
+	// can start draining traffic.
+	if conditions.GetReason(cluster, clusterv1.ControlPlaneEndpointServingCondition) != clusterv1.ControlPlaneEndpointTerminatedReason {
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneEndpointServingCondition, clusterv1.ControlPlaneEndpointTerminatingReason,
+			clusterv1.ConditionSeverityInfo, "Cluster is being deleted")
+	}
+
+	// If listing some kinds of descendants fails, still make progress on the kinds listed successfully,
+	// and return the error only afterwards.
+	descendants, listErr := r.listDescendants(ctx, cluster)
+	if listErr != nil && descendants.length() == 0 {
+		return r.listDescendantsFailed(cluster, listErr)
+	}
+
+	// Repair the owner references before looking for the direct descendants, so descendants which lost
+	// the Cluster owner reference are still deleted.
+	if err := r.repairDescendantOwnerReferences(ctx, cluster, &descendants); err != nil {
+		logger.Error(err, "Failed to repair owner references of descendants")
+		return reconcile.Result{}, err
+	}
+
+	// Delete the empty MachineSets right away, instead of waiting for their owners to be deleted.
+	if err := r.deleteEmptyMachineSets(ctx, cluster, &descendants); err != nil {
+		logger.Error(err, "Failed to delete empty MachineSets")
 		return reconcile.Result{}, err
 	}
 
-	children, err := descendants.filterOwnedDescendants(cluster)
+	// Delete the direct descendants while iterating over them, in the requested order, if any.
+	descendants.interleaveMachines = r.ParallelMachineDeletion
+	deletionOrder, err := r.deletionOrder(ctx, cluster)
 	if err != nil {
-		logger.Error(err, "Failed to extract direct descendants")
 		return reconcile.Result{}, err
 	}
+	descendants.deletionOrder = deletionOrder
+	var deleteOpts []client.DeleteOption
+	var errs []error
+	children := 0
+	workerMachinesDeleted := false
+	var deferredFor time.Duration
+
+	if err := descendants.eachOwnedDescendant(cluster, func(child runtime.Object) error {
+		if children == 0 {
+			var err error
+			if deleteOpts, err = descendantDeleteOptions(cluster); err != nil {
+				return err
+			}
+		}
+		children++
 
-	if len(children) > 0 {
-		logger.Info("Cluster still has children - deleting them first", "count", len(children))
+		accessor, err := meta.Accessor(child)
+		if err != nil {
+			logger.Error(err, "Couldn't create accessor", "type", fmt.Sprintf("%T", child))
+			return nil
+		}
 
-		var errs []error
+		if !accessor.GetDeletionTimestamp().IsZero() {
+			// Don't handle deleted child
+			return nil
+		}
 
-		for _, child := range children {
-			accessor, err := meta.Accessor(child)
-			if err != nil {
-				logger.Error(err, "Couldn't create accessor", "type", fmt.Sprintf("%T", child))
-				continue
+		// Delete all the worker Machines at once, if requested; given that descendants are sorted
+		// with control plane Machines last, this happens before any control plane Machine is deleted,
+		// unless ParallelMachineDeletion is set.
+		if machine, ok := child.(*clusterv1.Machine); ok && !util.IsControlPlaneMachine(machine) && r.canDeleteWorkerMachinesInBulk(cluster, &descendants) {
+			if workerMachinesDeleted {
+				return nil
 			}
+			workerMachinesDeleted = true
 
-			if !accessor.GetDeletionTimestamp().IsZero() {
-				// Don't handle deleted child
-				continue
+			logger.Info("Deleting worker Machines")
+			if err := r.deleteWorkerMachines(ctx, cluster, deleteOpts); err != nil {
+				logger.Error(err, "Error deleting worker Machines")
+				errs = append(errs, err)
 			}
+			return nil
+		}
 
-			gvk := child.GetObjectKind().GroupVersionKind().String()
+		gvk := child.GetObjectKind().GroupVersionKind().String()
 
-			logger.Info("Deleting child", "gvk", gvk, "name", accessor.GetName())
-			if err := r.Client.Delete(context.Background(), child); err != nil {
-				err = errors.Wrapf(err, "error deleting cluster %s/%s: failed to delete %s %s", cluster.Namespace, cluster.Name, gvk, accessor.GetName())
-				logger.Error(err, "Error deleting resource", "gvk", gvk, "name", accessor.GetName())
-				errs = append(errs, err)
+		// Defer the deletion of descendants created too recently, requeuing once they are old enough.
+		if remaining := r.MinDescendantAgeBeforeDeletion - r.now().Sub(accessor.GetCreationTimestamp().Time); remaining > 0 {
+			logger.Info("Deferring deletion of recently created child", "gvk", gvk, "name", accessor.GetName(), "remaining", remaining)
+			if deferredFor == 0 || remaining < deferredFor {
+				deferredFor = remaining
 			}
+			return nil
 		}
 
-		if len(errs) > 0 {
-			return ctrl.Result{}, kerrors.NewAggregate(errs)
+		if err := r.transformBeforeDelete(ctx, child); err != nil {
+			err = errors.Wrapf(err, "error deleting cluster %s/%s: failed to patch %s %s before deletion", cluster.Namespace, cluster.Name, gvk, accessor.GetName())
+			logger.Error(err, "Error patching resource", "gvk", gvk, "name", accessor.GetName())
+			errs = append(errs, err)
+			return nil
 		}
+
+		logger.Info("Deleting child", "gvk", gvk, "name", accessor.GetName())
+		if err := r.Client.Delete(context.Background(), child, deleteOpts...); err != nil {
+			err = errors.Wrapf(err, "error deleting cluster %s/%s: failed to delete %s %s", cluster.Namespace, cluster.Name, gvk, accessor.GetName())
+			logger.Error(err, "Error deleting resource", "gvk", gvk, "name", accessor.GetName())
+			errs = append(errs, err)
+			return nil
+		}
+		r.reconcileDescendantChurn(cluster, child, accessor)
+		return nil
+	}); err != nil {
+		logger.Error(err, "Failed to delete direct descendants")
+		return reconcile.Result{}, err
+	}
+
+	if children > 0 {
+		logger.Info("Cluster still has children - deleting them first", "count", children)
+	}
+
+	if len(errs) > 0 {
+		return ctrl.Result{}, kerrors.NewAggregate(errs)
+	}
+
+	if listErr != nil {
+		return r.listDescendantsFailed(cluster, listErr)
 	}
 
+	// Wait for the worker descendants to be gone before deleting the control plane object, so the workloads are not
+	// stranded by a control plane going away while the worker Machines are being drained; the control plane Machines
+	// managed by a control plane provider are not counted, given that the provider deletes them only afterwards.
 	if descendantCount := descendants.length(); descendantCount > 0 {
-		indirect := descendantCount - len(children)
-		logger.Info("Cluster still has descendants - need to requeue", "descendants", descendants.descendantNames(), "indirect descendants count", indirect)
-		// Requeue so we can check the next time to see if there are still any descendants left.
-		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+		owned := descendants.ownedLength(cluster)
+		logger.Info("Cluster still has descendants - need to requeue", "descendants", descendants.descendantNames(),
+			"owned descendants count", owned, "indirect descendants count", descendantCount-owned)
+		// Requeue so we can check the next time to see if there are still any descendants left,
+		// or sooner if the deletion of some descendants has been deferred.
+		requeueAfter := deleteRequeueAfter
+		if deferredFor > 0 && deferredFor < requeueAfter {
+			requeueAfter = deferredFor
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	if cluster.Spec.ControlPlaneRef != nil {
-		obj, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		obj, err := r.getExternal(ctx, cluster, cluster.Spec.ControlPlaneRef)
 		switch {
 		case apierrors.IsNotFound(errors.Cause(err)):
-			// All good - the control plane resource has been deleted
+			// All good - the control plane resource has been deleted, so its endpoint is not served anymore.
+			cluster.Status.ControlPlaneReady = false
+			conditions.MarkFalse(cluster, clusterv1.ControlPlaneEndpointServingCondition, clusterv1.ControlPlaneEndpointTerminatedReason,
+				clusterv1.ConditionSeverityInfo, "%s %q has been deleted", cluster.Spec.ControlPlaneRef.Kind, cluster.Spec.ControlPlaneRef.Name)
 		case err != nil:
-			return reconcile.Result{}, errors.Wrapf(err, "failed to get %s %q for Cluster %s/%s",
+			return reconcile.Result{}, errors.Wrapf(&capierrors.DeletionBlockedError{Kind: cluster.Spec.ControlPlaneRef.Kind, Names: []string{cluster.Spec.ControlPlaneRef.Name}, Err: err},
+				"failed to get %s %q for Cluster %s/%s",
 				path.Join(cluster.Spec.ControlPlaneRef.APIVersion, cluster.Spec.ControlPlaneRef.Kind),
 				cluster.Spec.ControlPlaneRef.Name, cluster.Namespace, cluster.Name)
 		default:
+			// Always requeue as a safety net, given that the control plane kind might not be watched; if the control plane
+			// object is already being deleted, report it once the grace period has elapsed.
+			requeueAfter := deleteRequeueAfter
+			if !obj.GetDeletionTimestamp().IsZero() {
+				if remaining := r.reconcileControlPlaneDeleting(cluster, obj); remaining > 0 && remaining < requeueAfter {
+					requeueAfter = remaining
+				}
+			}
+
 			// Issue a deletion request for the control plane object.
 			// Once it's been deleted, the cluster will get processed again.
 			if err := r.Client.Delete(ctx, obj); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err,
+				return ctrl.Result{}, errors.Wrapf(&capierrors.DeletionBlockedError{Kind: obj.GetKind(), Names: []string{obj.GetName()}, Err: err},
+					"failed to delete %v %q for Cluster %q in namespace %q",
+					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
+			}
+
+			// Return here so we don't remove the finalizer yet.
+			logger.Info("Cluster still has descendants - need to requeue", "controlPlaneRef", cluster.Spec.ControlPlaneRef.Name)
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
+
+	// If the control plane and the infrastructure references point to the same object, it has already been deleted above.
+	if cluster.Spec.InfrastructureRef != nil && !identicalReferences(cluster, cluster.Spec.ControlPlaneRef, cluster.Spec.InfrastructureRef) {
+		obj, err := r.getExternal(ctx, cluster, cluster.Spec.InfrastructureRef)
+		switch {
+		case apierrors.IsNotFound(errors.Cause(err)):
+			// All good - the infra resource has been deleted
+		case err != nil:
+			return ctrl.Result{}, errors.Wrapf(&capierrors.DeletionBlockedError{Kind: cluster.Spec.InfrastructureRef.Kind, Names: []string{cluster.Spec.InfrastructureRef.Name}, Err: err},
+				"failed to get %s %q for Cluster %s/%s",
+				path.Join(cluster.Spec.InfrastructureRef.APIVersion, cluster.Spec.InfrastructureRef.Kind),
+				cluster.Spec.InfrastructureRef.Name, cluster.Namespace, cluster.Name)
+		case annotations.HasPausedAnnotation(obj):
+			// Do not delete a paused infrastructure object, e.g. while an operator is inspecting it;
+			// requeue as a safety net, given that the infrastructure kind might not be watched.
+			conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletionPausedReason,
+				clusterv1.ConditionSeverityInfo, "%s %q is paused, deletion is deferred until it is unpaused", obj.GetKind(), obj.GetName())
+			logger.Info("Infrastructure object is paused - need to requeue", "infrastructureRef", cluster.Spec.InfrastructureRef.Name)
+			return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+		default:
+			// Issue a deletion request for the infrastructure object.
+			// Once it's been deleted, the cluster will get processed again.
+			if err := r.Client.Delete(ctx, obj); err != nil {
+				return ctrl.Result{}, errors.Wrapf(&capierrors.DeletionBlockedError{Kind: obj.GetKind(), Names: []string{obj.GetName()}, Err: err},
 					"failed to delete %v %q for Cluster %q in namespace %q",
 					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
 			}
 
-			// Return here so we don't remove the finalizer yet.
-			logger.Info("Cluster still has descendants - need to requeue", "controlPlaneRef", cluster.Spec.ControlPlaneRef.Name)
-			return ctrl.Result{}, nil
-		}
+			// Return here so we don't remove the finalizer yet.
+			logger.Info("Cluster still has descendants - need to requeue", "infrastructureRef", cluster.Spec.InfrastructureRef.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if r.DeleteClusterSecrets {
+		if err := r.deleteClusterSecrets(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.deleteSecretsInOtherNamespaces(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return r.removeFinalizerIfNoDescendants(ctx, cluster)
+}
+
+// deletionOrder returns the kinds of descendants in the order they are deleted, as listed in the ConfigMap named by
+// the DeletionOrderConfigMapAnnotation of a Cluster, if any.
+func (r *ClusterReconciler) deletionOrder(ctx context.Context, cluster *clusterv1.Cluster) ([]string, error) {
+	name, ok := cluster.Annotations[clusterv1.DeletionOrderConfigMapAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, configMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to get deletion order ConfigMap %q for Cluster %q in namespace %q",
+			name, cluster.Name, cluster.Namespace)
+	}
+
+	return strings.FieldsFunc(configMap.Data[deletionOrderConfigMapKey], func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}), nil
+}
+
+// listDescendantsFailed returns the result of a Cluster deletion which failed listing the descendants of the Cluster,
+// requeuing without an error if listing timed out.
+func (r *ClusterReconciler) listDescendantsFailed(cluster *clusterv1.Cluster, err error) (ctrl.Result, error) {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	if requeueErr, ok := errors.Cause(err).(capierrors.HasRequeueAfterError); ok {
+		logger.Info("Listing descendants timed out - need to requeue", "error", err.Error())
+		return ctrl.Result{RequeueAfter: requeueErr.GetRequeueAfter()}, nil
+	}
+	logger.Error(err, "Failed to list descendants")
+	return ctrl.Result{}, err
+}
+
+// transformBeforeDelete calls the DeleteTransformer, if any, on a descendant about to be deleted, and patches the changes.
+func (r *ClusterReconciler) transformBeforeDelete(ctx context.Context, obj runtime.Object) error {
+	if r.DeleteTransformer == nil {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(obj, r.Client)
+	if err != nil {
+		return err
+	}
+	r.DeleteTransformer(obj)
+	return patchHelper.Patch(ctx, obj)
+}
+
+// reconcileControlPlaneDeleting mirrors the state of a control plane object being deleted into the ControlPlaneReadyCondition,
+// falling back to ControlPlaneDeleting, only after the object has been in deletion for the configured grace period.
+// It returns the time left before the grace period elapses, if any.
+func (r *ClusterReconciler) reconcileControlPlaneDeleting(cluster *clusterv1.Cluster, controlPlane *unstructured.Unstructured) time.Duration {
+	if remaining := r.controlPlaneDeletingGracePeriod() - r.now().Sub(controlPlane.GetDeletionTimestamp().Time); remaining > 0 {
+		return remaining
+	}
+
+	r.mirrorReadyCondition(cluster, clusterv1.ControlPlaneReadyCondition, controlPlane, false, clusterv1.ControlPlaneDeletingReason)
+	return 0
+}
+
+// deleteClusterSecrets deletes the Secrets labeled with the cluster name and owned by the Cluster.
+func (r *ClusterReconciler) deleteClusterSecrets(ctx context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	secrets := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secrets,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return errors.Wrapf(err, "failed to list Secrets for Cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	var errs []error
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !util.IsOwnedByObject(secret, cluster) || !secret.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		logger.Info("Deleting Secret", "name", secret.Name)
+		if err := r.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete Secret %s/%s for Cluster %s/%s",
+				secret.Namespace, secret.Name, cluster.Namespace, cluster.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// deleteSecretsInOtherNamespaces deletes the Secrets labeled with the cluster name in the SecretNamespaces, if any.
+// NOTE: Secrets in other namespaces can't be owned by the Cluster, so they are matched by label only.
+func (r *ClusterReconciler) deleteSecretsInOtherNamespaces(ctx context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	var errs []error
+	for _, namespace := range r.SecretNamespaces {
+		if namespace == cluster.Namespace {
+			continue
+		}
+
+		secrets := &corev1.SecretList{}
+		if err := r.Client.List(ctx, secrets,
+			client.InNamespace(namespace),
+			client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+		); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to list Secrets in namespace %q for Cluster %s/%s", namespace, cluster.Namespace, cluster.Name))
+			continue
+		}
+
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			if uid, ok := secret.Labels[clusterv1.ClusterUIDLabelName]; (ok && uid != string(cluster.UID)) || !secret.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			logger.Info("Deleting Secret", "name", secret.Name, "secretNamespace", secret.Namespace)
+			if err := r.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, errors.Wrapf(err, "failed to delete Secret %s/%s for Cluster %s/%s",
+					secret.Namespace, secret.Name, cluster.Namespace, cluster.Name))
+			}
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// deleteEmptyMachineSets deletes the MachineSets of a Cluster being deleted which are scaled to zero replicas and
+// don't have any Machine, even if they are not owned by the Cluster, e.g. MachineSets owned by a MachineDeployment.
+// NOTE: This must be called only when the Cluster is being deleted, given that MachineSets scaled to zero are
+// legitimately retained by a live MachineDeployment, e.g. for rollbacks.
+func (r *ClusterReconciler) deleteEmptyMachineSets(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	hasMachines := func(ms *clusterv1.MachineSet) bool {
+		for _, machines := range []clusterv1.MachineList{descendants.workerMachines, descendants.controlPlaneMachines} {
+			for i := range machines.Items {
+				for _, ref := range machines.Items[i].OwnerReferences {
+					if ref.Kind == "MachineSet" && ref.Name == ms.Name {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	var errs []error
+	for i := range descendants.machineSets.Items {
+		ms := &descendants.machineSets.Items[i]
+		// MachineSets owned by the Cluster are deleted together with the other direct descendants.
+		if !ms.DeletionTimestamp.IsZero() || util.IsOwnedByObject(ms, cluster) {
+			continue
+		}
+		if ms.Spec.Replicas == nil || *ms.Spec.Replicas != 0 || hasMachines(ms) {
+			continue
+		}
+
+		logger.Info("Deleting empty MachineSet", "name", ms.Name)
+		if err := r.Client.Delete(ctx, ms); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "error deleting cluster %s/%s: failed to delete empty MachineSet %s",
+				cluster.Namespace, cluster.Name, ms.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// canDeleteWorkerMachinesInBulk returns true if the worker Machines of a Cluster can be deleted with a single
+// DeleteAllOf call, i.e. if DeleteWorkerMachinesInBulk is set, none of the options applying to each descendant
+// being deleted is set, and the DeleteAllOf call can't delete Machines owned by other objects, e.g. MachineSets.
+func (r *ClusterReconciler) canDeleteWorkerMachinesInBulk(cluster *clusterv1.Cluster, descendants *clusterDescendants) bool {
+	if !r.DeleteWorkerMachinesInBulk || r.DeleteTransformer != nil || r.MinDescendantAgeBeforeDeletion > 0 {
+		return false
+	}
+	if len(descendants.machineSets.Items) > 0 {
+		return false
+	}
+	for i := range descendants.workerMachines.Items {
+		for _, ref := range descendants.workerMachines.Items[i].OwnerReferences {
+			if ref.Kind != "Cluster" || ref.Name != cluster.Name {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// deleteWorkerMachines deletes all the worker Machines of a Cluster using a single DeleteAllOf call.
+func (r *ClusterReconciler) deleteWorkerMachines(ctx context.Context, cluster *clusterv1.Cluster, deleteOpts []client.DeleteOption) error {
+	clusterRequirement, err := labels.NewRequirement(clusterv1.ClusterLabelName, selection.Equals, []string{cluster.Name})
+	if err != nil {
+		return err
+	}
+	workerRequirement, err := labels.NewRequirement(clusterv1.MachineControlPlaneLabelName, selection.DoesNotExist, nil)
+	if err != nil {
+		return err
+	}
+
+	opts := []client.DeleteAllOfOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*clusterRequirement, *workerRequirement)},
+	}
+	for _, opt := range deleteOpts {
+		if o, ok := opt.(client.DeleteAllOfOption); ok {
+			opts = append(opts, o)
+		}
+	}
+
+	if err := r.Client.DeleteAllOf(ctx, &clusterv1.Machine{}, opts...); err != nil {
+		return errors.Wrapf(err, "error deleting cluster %s/%s: failed to delete worker Machines", cluster.Namespace, cluster.Name)
+	}
+	return nil
+}
+
+// reconcileDescendantChurn records the deletion of a descendant of a Cluster, reporting in the DescendantsStableCondition
+// a descendant deleted more times than the DescendantChurnThreshold, i.e. recreated as fast as it is deleted.
+func (r *ClusterReconciler) reconcileDescendantChurn(cluster *clusterv1.Cluster, child runtime.Object, accessor metav1.Object) {
+	kind := child.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = fmt.Sprintf("%T", child)
+	}
+
+	deletions := r.descendantDeletions.record(cluster.UID, kind+"/"+accessor.GetName(), accessor.GetUID())
+	if deletions <= r.descendantChurnThreshold() {
+		return
+	}
+
+	if !conditions.IsFalse(cluster, clusterv1.DescendantsStableCondition) {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DescendantChurning",
+			"%s %q has been deleted %d times, it might be recreated by another controller", kind, accessor.GetName(), deletions)
 	}
+	conditions.MarkFalse(cluster, clusterv1.DescendantsStableCondition, clusterv1.DescendantChurningReason, clusterv1.ConditionSeverityWarning,
+		"%s %q has been deleted %d times, it might be recreated by another controller", kind, accessor.GetName(), deletions)
+}
 
-	if cluster.Spec.InfrastructureRef != nil {
-		obj, err := external.Get(ctx, r.Client, cluster.Spec.InfrastructureRef, cluster.Namespace)
-		switch {
-		case apierrors.IsNotFound(errors.Cause(err)):
-			// All good - the infra resource has been deleted
-		case err != nil:
-			return ctrl.Result{}, errors.Wrapf(err, "failed to get %s %q for Cluster %s/%s",
-				path.Join(cluster.Spec.InfrastructureRef.APIVersion, cluster.Spec.InfrastructureRef.Kind),
-				cluster.Spec.InfrastructureRef.Name, cluster.Namespace, cluster.Name)
-		default:
-			// Issue a deletion request for the infrastructure object.
-			// Once it's been deleted, the cluster will get processed again.
-			if err := r.Client.Delete(ctx, obj); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err,
-					"failed to delete %v %q for Cluster %q in namespace %q",
-					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
-			}
+// removeFinalizerIfNoDescendants removes the Cluster finalizer only after confirming there are no descendants left,
+// thus guarding against descendants created after the cluster deletion started.
+func (r *ClusterReconciler) removeFinalizerIfNoDescendants(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
 
-			// Return here so we don't remove the finalizer yet.
-			logger.Info("Cluster still has descendants - need to requeue", "infrastructureRef", cluster.Spec.InfrastructureRef.Name)
-			return ctrl.Result{}, nil
-		}
+	descendants, err := r.listDescendants(ctx, cluster)
+	if err != nil {
+		return r.listDescendantsFailed(cluster, err)
+	}
+
+	if descendants.length() > 0 {
+		logger.Info("Cluster has new descendants - need to requeue", "descendants", descendants.descendantNames())
+		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
 	}
 
+	// A Cluster handled as being deleted because its namespace is terminating is not deleted yet,
+	// so it keeps its finalizer until it is.
+	if cluster.DeletionTimestamp.IsZero() && conditions.GetReason(cluster, clusterv1.NamespaceActiveCondition) == clusterv1.NamespaceTerminatingReason {
+		logger.Info("Cluster has no descendants left, waiting for the Cluster to be deleted")
+		return ctrl.Result{}, nil
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ClusterDeleted", "Cluster %q has been deleted", cluster.Name)
+	r.descendantDeletions.forget(cluster.UID)
+	r.infrastructureNotFound.forget(cluster.UID)
+	r.controlPlaneProbeClients.forget(cluster.UID)
 	controllerutil.RemoveFinalizer(cluster, clusterv1.ClusterFinalizer)
+	metrics.ClusterFinalizerRemoved.Inc()
 	return ctrl.Result{}, nil
 }
 
+// descendantDeleteOptions returns the options to be used when deleting the descendants of a Cluster,
+// honoring the grace period defined by the DeleteGraceSecondsAnnotation, if any.
+func descendantDeleteOptions(cluster *clusterv1.Cluster) ([]client.DeleteOption, error) {
+	value, ok := cluster.Annotations[clusterv1.DeleteGraceSecondsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return nil, errors.Errorf("invalid value %q for annotation %q on Cluster %q in namespace %q: must be a non-negative integer",
+			value, clusterv1.DeleteGraceSecondsAnnotation, cluster.Name, cluster.Namespace)
+	}
+	return []client.DeleteOption{client.GracePeriodSeconds(seconds)}, nil
+}
+
 type clusterDescendants struct {
 	machineDeployments   clusterv1.MachineDeploymentList
 	machineSets          clusterv1.MachineSetList
 	controlPlaneMachines clusterv1.MachineList
 	workerMachines       clusterv1.MachineList
+	machinePools         expv1.MachinePoolList
+	bootstrapConfigs     unstructured.UnstructuredList
+	custom               []customDescendants
+
+	// interleaveMachines returns the control plane and the worker Machines interleaved, instead of
+	// the control plane Machines last.
+	interleaveMachines bool
+
+	// deletionOrder are the kinds of descendants, e.g. "MachineDeployment", in the order they are returned;
+	// kinds not listed are returned afterwards in the default order, and control plane Machines always last.
+	deletionOrder []string
+}
+
+// customDescendants are the descendants of a custom kind registered with RegisterDescendantKind.
+type customDescendants struct {
+	// kind is the kind of the descendants, e.g. "MachineSet".
+	kind string
+	list runtime.Object
 }
 
 // length returns the number of descendants
@@ -324,7 +1691,115 @@ func (c *clusterDescendants) length() int {
 	return len(c.machineDeployments.Items) +
 		len(c.machineSets.Items) +
 		len(c.controlPlaneMachines.Items) +
-		len(c.workerMachines.Items)
+		len(c.workerMachines.Items) +
+		len(c.machinePools.Items) +
+		len(c.bootstrapConfigs.Items) +
+		c.customLength()
+}
+
+// customLength returns the number of descendants of custom kinds.
+func (c *clusterDescendants) customLength() int {
+	count := 0
+	for _, custom := range c.custom {
+		count += meta.LenList(custom.list)
+	}
+	return count
+}
+
+// ownedLength returns the number of descendants having the cluster as an owner reference.
+func (c *clusterDescendants) ownedLength(cluster *clusterv1.Cluster) int {
+	count := 0
+	for _, list := range c.lists() {
+		_ = meta.EachListItem(list, func(o runtime.Object) error {
+			if acc, err := meta.Accessor(o); err == nil && util.IsOwnedByObject(acc, cluster) {
+				count++
+			}
+			return nil
+		})
+	}
+	return count
+}
+
+// lists returns the lists of descendants, with owners sorted before the objects they might own, e.g. standalone
+// MachineSets before their Machines, and control plane machines last, unless machines are interleaved; descendants
+// of custom kinds, which might own Machines too, are sorted before Machines.
+func (c *clusterDescendants) lists() []runtime.Object {
+	type kindList struct {
+		kind string
+		list runtime.Object
+	}
+	kindLists := []kindList{
+		{"MachinePool", &c.machinePools},
+		{"MachineDeployment", &c.machineDeployments},
+		{"MachineSet", &c.machineSets},
+	}
+	for _, custom := range c.custom {
+		kindLists = append(kindLists, kindList{custom.kind, custom.list})
+	}
+	if c.interleaveMachines {
+		kindLists = append(kindLists, kindList{"Machine", c.interleavedMachines()})
+	} else {
+		kindLists = append(kindLists, kindList{"Machine", &c.workerMachines})
+	}
+
+	if len(c.deletionOrder) > 0 {
+		rank := func(kind string) int {
+			for i, k := range c.deletionOrder {
+				if k == kind {
+					return i
+				}
+			}
+			return len(c.deletionOrder)
+		}
+		sort.SliceStable(kindLists, func(i, j int) bool {
+			return rank(kindLists[i].kind) < rank(kindLists[j].kind)
+		})
+	}
+
+	lists := make([]runtime.Object, 0, len(kindLists)+1)
+	for _, kl := range kindLists {
+		lists = append(lists, kl.list)
+	}
+	if c.interleaveMachines {
+		return lists
+	}
+	return append(lists, &c.controlPlaneMachines)
+}
+
+// filterByClusterUID removes the descendants labeled with a Cluster UID other than the given one;
+// descendants without the ClusterUIDLabelName label are retained.
+func (c *clusterDescendants) filterByClusterUID(uid types.UID) {
+	for _, list := range append(c.lists(), &c.bootstrapConfigs) {
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			continue
+		}
+		kept := make([]runtime.Object, 0, len(items))
+		for _, item := range items {
+			if acc, err := meta.Accessor(item); err == nil {
+				if value, ok := acc.GetLabels()[clusterv1.ClusterUIDLabelName]; ok && value != string(uid) {
+					continue
+				}
+			}
+			kept = append(kept, item)
+		}
+		_ = meta.SetList(list, kept)
+	}
+}
+
+// interleavedMachines returns a list with the control plane and the worker Machines interleaved, starting with
+// a control plane Machine.
+func (c *clusterDescendants) interleavedMachines() *clusterv1.MachineList {
+	machines := &clusterv1.MachineList{}
+	for i := 0; i < len(c.controlPlaneMachines.Items) || i < len(c.workerMachines.Items); i++ {
+		if i < len(c.controlPlaneMachines.Items) {
+			machines.Items = append(machines.Items, c.controlPlaneMachines.Items[i])
+		}
+		if i < len(c.workerMachines.Items) {
+			machines.Items = append(machines.Items, c.workerMachines.Items[i])
+		}
+	}
+	return machines
 }
 
 func (c *clusterDescendants) descendantNames() string {
@@ -357,11 +1832,207 @@ func (c *clusterDescendants) descendantNames() string {
 	if len(workerMachineNames) > 0 {
 		descendants = append(descendants, "Worker machines: "+strings.Join(workerMachineNames, ","))
 	}
+	machinePoolNames := make([]string, len(c.machinePools.Items))
+	for i, machinePool := range c.machinePools.Items {
+		machinePoolNames[i] = machinePool.Name
+	}
+	if len(machinePoolNames) > 0 {
+		descendants = append(descendants, "Machine pools: "+strings.Join(machinePoolNames, ","))
+	}
+	bootstrapConfigNames := make([]string, len(c.bootstrapConfigs.Items))
+	for i, bootstrapConfig := range c.bootstrapConfigs.Items {
+		bootstrapConfigNames[i] = bootstrapConfig.GetKind() + "/" + bootstrapConfig.GetName()
+	}
+	if len(bootstrapConfigNames) > 0 {
+		descendants = append(descendants, "Bootstrap configs: "+strings.Join(bootstrapConfigNames, ","))
+	}
+	for _, custom := range c.custom {
+		var customNames []string
+		_ = meta.EachListItem(custom.list, func(o runtime.Object) error {
+			if acc, err := meta.Accessor(o); err == nil {
+				customNames = append(customNames, acc.GetName())
+			}
+			return nil
+		})
+		if len(customNames) > 0 {
+			descendants = append(descendants, custom.kind+": "+strings.Join(customNames, ","))
+		}
+	}
 	return strings.Join(descendants, ";")
 }
 
-// listDescendants returns a list of all MachineDeployments, MachineSets, and Machines for the cluster.
+// descendantTopology is a compact summary of the descendants of a Cluster, exported in the DescendantTopologyAnnotation.
+type descendantTopology struct {
+	// Counts is the number of descendants by kind.
+	Counts map[string]int `json:"counts"`
+
+	// Descendants are the descendants along with their controller, if any, so the descendant tree can be rebuilt.
+	Descendants []descendantTopologyNode `json:"descendants,omitempty"`
+
+	// Truncated is true if some descendants have been left out to keep the summary within the size limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// descendantTopologyNode is a descendant of a Cluster in a descendantTopology.
+type descendantTopologyNode struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Controller is the kind and name of the controller of the descendant, e.g. "MachineSet/ms", if any.
+	Controller string `json:"controller,omitempty"`
+}
+
+// topology returns the topology of the descendants.
+func (c *clusterDescendants) topology() descendantTopology {
+	topology := descendantTopology{Counts: map[string]int{}}
+	add := func(kind string, list runtime.Object) {
+		_ = meta.EachListItem(list, func(o runtime.Object) error {
+			accessor, err := meta.Accessor(o)
+			if err != nil {
+				return nil
+			}
+			node := descendantTopologyNode{Kind: kind, Name: accessor.GetName()}
+			if node.Kind == "" {
+				node.Kind = o.GetObjectKind().GroupVersionKind().Kind
+			}
+			if ref := metav1.GetControllerOf(accessor); ref != nil {
+				node.Controller = ref.Kind + "/" + ref.Name
+			}
+			topology.Counts[node.Kind]++
+			topology.Descendants = append(topology.Descendants, node)
+			return nil
+		})
+	}
+
+	add("MachineDeployment", &c.machineDeployments)
+	add("MachineSet", &c.machineSets)
+	add("Machine", &c.controlPlaneMachines)
+	add("Machine", &c.workerMachines)
+	add("MachinePool", &c.machinePools)
+	// The kind of bootstrap configs is read from each of them.
+	add("", &c.bootstrapConfigs)
+	for _, custom := range c.custom {
+		add(custom.kind, custom.list)
+	}
+	return topology
+}
+
+// marshalDescendantTopology returns the JSON encoding of a descendant topology, leaving out as many trailing
+// descendants as required to fit within maxSize; the counts are always retained.
+func marshalDescendantTopology(topology descendantTopology, maxSize int) (string, error) {
+	data, err := json.Marshal(topology)
+	if err != nil || len(data) <= maxSize {
+		return string(data), err
+	}
+
+	// Look for the largest number of descendants fitting within maxSize.
+	descendants := topology.Descendants
+	topology.Truncated = true
+	var marshalErr error
+	n := sort.Search(len(descendants)+1, func(i int) bool {
+		topology.Descendants = descendants[:i]
+		data, err := json.Marshal(topology)
+		if err != nil {
+			marshalErr = err
+			return true
+		}
+		return len(data) > maxSize
+	})
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	if n > 0 {
+		n--
+	}
+
+	topology.Descendants = descendants[:n]
+	data, err = json.Marshal(topology)
+	return string(data), err
+}
+
+// listDescendantsTimeoutError is returned by listDescendants when listing one kind of descendants timed out.
+type listDescendantsTimeoutError struct {
+	// kind is the kind of the descendants which could not be listed, e.g. "MachineSet".
+	kind    string
+	timeout time.Duration
+}
+
+// Error implements the error interface
+func (e *listDescendantsTimeoutError) Error() string {
+	return fmt.Sprintf("listing %ss timed out after %s", e.kind, e.timeout)
+}
+
+// listDescendants returns the descendants of a Cluster, emitting a Warning event and asking to requeue
+// if listing one kind of descendants takes longer than the configured timeout.
+// In case of errors, the descendants of the kinds listed successfully are returned along with the error.
 func (r *ClusterReconciler) listDescendants(ctx context.Context, cluster *clusterv1.Cluster) (clusterDescendants, error) {
+	descendants, err := listDescendants(ctx, r.Client, cluster, r.listDescendantsTimeout(), r.BootstrapConfigKinds...)
+	if customErr := r.listCustomDescendants(ctx, cluster, &descendants); customErr != nil {
+		err = kerrors.NewAggregate([]error{err, customErr})
+	}
+	if r.FilterDescendantsByClusterUID {
+		descendants.filterByClusterUID(cluster.UID)
+	}
+	if timeoutErr := findListDescendantsTimeoutError(err); timeoutErr != nil {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "ListDescendantsTimeout",
+			"Listing %ss of the Cluster timed out after %s", timeoutErr.kind, timeoutErr.timeout)
+		return descendants, errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: listDescendantsTimeoutRequeueAfter},
+			"failed to list descendants for cluster %s/%s: %v", cluster.Namespace, cluster.Name, err)
+	}
+	return descendants, err
+}
+
+// findListDescendantsTimeoutError returns the first listDescendantsTimeoutError in the given error, if any,
+// including the errors it aggregates.
+func findListDescendantsTimeoutError(err error) *listDescendantsTimeoutError {
+	if err == nil {
+		return nil
+	}
+	if agg, ok := err.(kerrors.Aggregate); ok {
+		for _, e := range agg.Errors() {
+			if timeoutErr := findListDescendantsTimeoutError(e); timeoutErr != nil {
+				return timeoutErr
+			}
+		}
+		return nil
+	}
+	if timeoutErr, ok := errors.Cause(err).(*listDescendantsTimeoutError); ok {
+		return timeoutErr
+	}
+	return nil
+}
+
+// listCustomDescendants adds to the given descendants the ones of the custom kinds registered with RegisterDescendantKind.
+// Each List call is bounded by the configured timeout; all the kinds are listed even if listing some of them fails.
+func (r *ClusterReconciler) listCustomDescendants(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	timeout := r.listDescendantsTimeout()
+	var errs []error
+	for _, k := range r.descendantKinds {
+		kind := k.gvk.Kind
+		listCtx, cancel := context.WithTimeout(ctx, timeout)
+		list, err := k.listFn(listCtx, r.Client,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name}),
+		)
+		timedOut := listCtx.Err() == context.DeadlineExceeded
+		cancel()
+		if err != nil {
+			if timedOut {
+				err = &listDescendantsTimeoutError{kind: kind, timeout: timeout}
+			}
+			errs = append(errs, errors.Wrapf(err, "failed to list %ss for cluster %s/%s", kind, cluster.Namespace, cluster.Name))
+			continue
+		}
+		descendants.custom = append(descendants.custom, customDescendants{kind: kind, list: list})
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// listDescendants returns a list of all MachineDeployments, MachineSets, Machines, MachinePools
+// (if the MachinePool feature is enabled), and bootstrap configs of the given kinds for the cluster.
+// Each List call is bounded by the given timeout, if not zero. In case of errors, the descendants of the kinds
+// listed successfully are returned along with the aggregated errors.
+func listDescendants(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, timeout time.Duration, bootstrapConfigKinds ...schema.GroupVersionKind) (clusterDescendants, error) {
 	var descendants clusterDescendants
 
 	listOptions := []client.ListOption{
@@ -369,17 +2040,42 @@ func (r *ClusterReconciler) listDescendants(ctx context.Context, cluster *cluste
 		client.MatchingLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name}),
 	}
 
-	if err := r.Client.List(ctx, &descendants.machineDeployments, listOptions...); err != nil {
-		return descendants, errors.Wrapf(err, "failed to list MachineDeployments for cluster %s/%s", cluster.Namespace, cluster.Name)
+	listKind := func(kind string, list runtime.Object) error {
+		listCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			listCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		if err := c.List(listCtx, list, listOptions...); err != nil {
+			if listCtx.Err() == context.DeadlineExceeded {
+				err = &listDescendantsTimeoutError{kind: kind, timeout: timeout}
+			}
+			return errors.Wrapf(err, "failed to list %ss for cluster %s/%s", kind, cluster.Namespace, cluster.Name)
+		}
+		return nil
+	}
+
+	// List all the kinds even if listing some of them fails, so callers can still act on the kinds listed successfully.
+	var errs []error
+	if err := listKind("MachineDeployment", &descendants.machineDeployments); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := listKind("MachineSet", &descendants.machineSets); err != nil {
+		errs = append(errs, err)
 	}
 
-	if err := r.Client.List(ctx, &descendants.machineSets, listOptions...); err != nil {
-		return descendants, errors.Wrapf(err, "failed to list MachineSets for cluster %s/%s", cluster.Namespace, cluster.Name)
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := listKind("MachinePool", &descendants.machinePools); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	var machines clusterv1.MachineList
-	if err := r.Client.List(ctx, &machines, listOptions...); err != nil {
-		return descendants, errors.Wrapf(err, "failed to list Machines for cluster %s/%s", cluster.Namespace, cluster.Name)
+	if err := listKind("Machine", &machines); err != nil {
+		errs = append(errs, err)
 	}
 
 	// Split machines into control plane and worker machines so we make sure we delete control plane machines last
@@ -388,51 +2084,84 @@ func (r *ClusterReconciler) listDescendants(ctx context.Context, cluster *cluste
 	// Only count control plane machines as descendants if there is no control plane provider.
 	if cluster.Spec.ControlPlaneRef == nil {
 		descendants.controlPlaneMachines = *controlPlaneMachines
+	}
 
+	// Bootstrap configs are not deleted by the Cluster controller, they are only counted so the Cluster
+	// is not removed before they are gone.
+	for _, gvk := range bootstrapConfigKinds {
+		bootstrapConfigs := &unstructured.UnstructuredList{}
+		bootstrapConfigs.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := listKind(gvk.Kind, bootstrapConfigs); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		descendants.bootstrapConfigs.Items = append(descendants.bootstrapConfigs.Items, bootstrapConfigs.Items...)
 	}
 
-	return descendants, nil
+	return descendants, kerrors.NewAggregate(errs)
+}
+
+// eachOwnedDescendant calls fn for each descendant having the cluster as an owner reference, with control plane
+// machines last; the iteration stops at the first error returned by fn. Given that descendants are processed
+// one at a time, callers can act on them without gathering the full list first.
+func (c clusterDescendants) eachOwnedDescendant(cluster *clusterv1.Cluster, fn func(runtime.Object) error) error {
+	return c.eachDescendant(func(o metav1.Object) bool {
+		return util.IsOwnedByObject(o, cluster)
+	}, fn)
+}
+
+// eachDescendant calls fn for each descendant matching the given filter, with control plane machines last;
+// the iteration stops at the first error returned by fn.
+func (c clusterDescendants) eachDescendant(filter func(metav1.Object) bool, fn func(runtime.Object) error) error {
+	for _, list := range c.lists() {
+		if err := meta.EachListItem(list, func(o runtime.Object) error {
+			acc, err := meta.Accessor(o)
+			if err != nil {
+				return nil
+			}
+			if !filter(acc) {
+				return nil
+			}
+			return fn(o)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // filterOwnedDescendants returns an array of runtime.Objects containing only those descendants that have the cluster
 // as an owner reference, with control plane machines sorted last.
 func (c clusterDescendants) filterOwnedDescendants(cluster *clusterv1.Cluster) ([]runtime.Object, error) {
-	var ownedDescendants []runtime.Object
-	eachFunc := func(o runtime.Object) error {
-		acc, err := meta.Accessor(o)
-		if err != nil {
-			return nil
-		}
-
-		if util.IsOwnedByObject(acc, cluster) {
-			ownedDescendants = append(ownedDescendants, o)
-		}
+	return c.filterDescendants(cluster, func(o metav1.Object) bool {
+		return util.IsOwnedByObject(o, cluster)
+	})
+}
 
+// filterDescendants returns an array of runtime.Objects containing only those descendants matching the given filter,
+// with control plane machines sorted last.
+func (c clusterDescendants) filterDescendants(cluster *clusterv1.Cluster, filter func(metav1.Object) bool) ([]runtime.Object, error) {
+	var descendants []runtime.Object
+	if err := c.eachDescendant(filter, func(o runtime.Object) error {
+		descendants = append(descendants, o)
 		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "error finding descendants of cluster %s/%s", cluster.Namespace, cluster.Name)
 	}
-
-	lists := []runtime.Object{
-		&c.machineDeployments,
-		&c.machineSets,
-		&c.workerMachines,
-		&c.controlPlaneMachines,
-	}
-	for _, list := range lists {
-		if err := meta.EachListItem(list, eachFunc); err != nil {
-			return nil, errors.Wrapf(err, "error finding owned descendants of cluster %s/%s", cluster.Namespace, cluster.Name)
-		}
-	}
-
-	return ownedDescendants, nil
+	return descendants, nil
 }
 
 // splitMachineList separates the machines running the control plane from other worker nodes.
+// Machines backed by a MachinePool are left out, given that they are accounted for by their MachinePool.
 func splitMachineList(list *clusterv1.MachineList) (*clusterv1.MachineList, *clusterv1.MachineList) {
 	nodes := &clusterv1.MachineList{}
 	controlplanes := &clusterv1.MachineList{}
 	for i := range list.Items {
 		machine := &list.Items[i]
-		if util.IsControlPlaneMachine(machine) {
+		if util.IsMachinePoolMachine(machine) {
+			continue
+		}
+		if util.ClassifyDescendant(machine) == util.DescendantTierControlPlane {
 			controlplanes.Items = append(controlplanes.Items, *machine)
 		} else {
 			nodes.Items = append(nodes.Items, *machine)
@@ -449,7 +2178,7 @@ func (r *ClusterReconciler) reconcileControlPlaneInitialized(ctx context.Context
 		return nil
 	}
 
-	if cluster.Status.ControlPlaneInitialized {
+	if cluster.Status.ControlPlaneInitialized && !r.ResetControlPlaneInitialized {
 		return nil
 	}
 
@@ -466,9 +2195,61 @@ func (r *ClusterReconciler) reconcileControlPlaneInitialized(ctx context.Context
 		}
 	}
 
+	if feature.Gates.Enabled(feature.MachinePool) {
+		machinePools, err := getControlPlaneMachinePoolsInCluster(ctx, r.Client, cluster.Namespace, cluster.Name)
+		if err != nil {
+			logger.Error(err, "Error getting control plane machine pools in cluster")
+			return err
+		}
+
+		for _, mp := range machinePools {
+			if mp.Status.ReadyReplicas > 0 {
+				cluster.Status.ControlPlaneInitialized = true
+				return nil
+			}
+		}
+	}
+
+	// If there is no initialized control plane left, e.g. during a full rebuild of the control plane,
+	// the ControlPlaneInitialized flag is stale; this can only happen when resetting it is enabled.
+	if cluster.Status.ControlPlaneInitialized {
+		logger.Info("No initialized control plane Machine left, resetting ControlPlaneInitialized")
+		cluster.Status.ControlPlaneInitialized = false
+	}
+
 	return nil
 }
 
+// controlPlaneMachinePoolToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update its status.controlPlaneInitialized field when using control plane MachinePools.
+func (r *ClusterReconciler) controlPlaneMachinePoolToCluster(o handler.MapObject) []ctrl.Request {
+	mp, ok := o.Object.(*expv1.MachinePool)
+	if !ok {
+		r.Log.Error(nil, fmt.Sprintf("Expected a MachinePool but got a %T", o.Object))
+		return nil
+	}
+	if _, ok := mp.Labels[clusterv1.MachineControlPlaneLabelName]; !ok {
+		return nil
+	}
+	if mp.Status.ReadyReplicas == 0 {
+		return nil
+	}
+
+	cluster, err := util.GetClusterByName(context.TODO(), r.Client, mp.Namespace, mp.Spec.ClusterName)
+	if err != nil {
+		r.Log.Error(err, "Failed to get cluster", "machinePool", mp.Name, "cluster", mp.Spec.ClusterName, "namespace", mp.Namespace)
+		return nil
+	}
+
+	if cluster.Status.ControlPlaneInitialized {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: util.ObjectKey(cluster),
+	}}
+}
+
 // controlPlaneMachineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
 // for Cluster to update its status.controlPlaneInitialized field
 func (r *ClusterReconciler) controlPlaneMachineToCluster(o handler.MapObject) []ctrl.Request {
@@ -477,24 +2258,147 @@ func (r *ClusterReconciler) controlPlaneMachineToCluster(o handler.MapObject) []
 		r.Log.Error(nil, fmt.Sprintf("Expected a Machine but got a %T", o.Object))
 		return nil
 	}
-	if !util.IsControlPlaneMachine(m) {
+	return r.controlPlaneMachinesToClusters(m)
+}
+
+// controlPlaneMachinesToClusters returns a deduplicated list of requests for the Clusters
+// which are waiting for the control plane to be initialized by the given Machines.
+func (r *ClusterReconciler) controlPlaneMachinesToClusters(machines ...*clusterv1.Machine) []ctrl.Request {
+	requests := util.RequestSet{}
+	for _, m := range machines {
+		if !util.IsControlPlaneMachine(m) {
+			continue
+		}
+		if m.Status.NodeRef == nil {
+			continue
+		}
+
+		cluster, err := util.GetClusterByName(context.TODO(), r.Client, m.Namespace, m.Spec.ClusterName)
+		if err != nil {
+			r.Log.Error(err, "Failed to get cluster", "machine", m.Name, "cluster", m.ClusterName, "namespace", m.Namespace)
+			continue
+		}
+
+		if cluster.Status.ControlPlaneInitialized {
+			continue
+		}
+
+		requests.Insert(util.ObjectKeyWithGVK(clusterv1.GroupVersion.WithKind("Cluster"), cluster))
+	}
+	return requests.Requests()
+}
+
+// crdToClusters is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for the Clusters referencing objects of the kind defined by an established CustomResourceDefinition;
+// this allows Clusters created before the provider CRDs were installed to make progress.
+func (r *ClusterReconciler) crdToClusters(o handler.MapObject) []ctrl.Request {
+	crd, ok := o.Object.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		r.Log.Error(nil, fmt.Sprintf("Expected a CustomResourceDefinition but got a %T", o.Object))
 		return nil
 	}
-	if m.Status.NodeRef == nil {
+	if !crdEstablished(crd) {
 		return nil
 	}
+	groupKind := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
 
-	cluster, err := util.GetClusterByName(context.TODO(), r.Client, m.Namespace, m.Spec.ClusterName)
-	if err != nil {
-		r.Log.Error(err, "Failed to get cluster", "machine", m.Name, "cluster", m.ClusterName, "namespace", m.Namespace)
+	clusters := &clusterv1.ClusterList{}
+	if err := r.Client.List(context.TODO(), clusters); err != nil {
+		r.Log.Error(err, "Failed to list clusters", "customResourceDefinition", crd.Name)
 		return nil
 	}
 
-	if cluster.Status.ControlPlaneInitialized {
+	requests := util.RequestSet{}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		for _, ref := range []*corev1.ObjectReference{cluster.Spec.InfrastructureRef, cluster.Spec.ControlPlaneRef} {
+			if ref != nil && ref.GroupVersionKind().GroupKind() == groupKind {
+				requests.Insert(util.ObjectKeyWithGVK(clusterv1.GroupVersion.WithKind("Cluster"), cluster))
+			}
+		}
+	}
+	return requests.Requests()
+}
+
+// crdEstablished returns true if the CustomResourceDefinition has the Established condition set to true.
+func crdEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.Established {
+			return c.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// kubeconfigSecretToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for the Cluster a Kubeconfig Secret belongs to, so changes made to the Secret by others are repaired.
+func (r *ClusterReconciler) kubeconfigSecretToCluster(o handler.MapObject) []ctrl.Request {
+	s, ok := o.Object.(*corev1.Secret)
+	if !ok {
+		r.Log.Error(nil, fmt.Sprintf("Expected a Secret but got a %T", o.Object))
+		return nil
+	}
+	if !isKubeconfigSecret(s) {
 		return nil
 	}
 
 	return []ctrl.Request{{
-		NamespacedName: util.ObjectKey(cluster),
+		NamespacedName: client.ObjectKey{Namespace: s.Namespace, Name: s.Labels[clusterv1.ClusterLabelName]},
 	}}
 }
+
+// kubeconfigSecrets returns a predicate filtering out the events for Secrets other than the kubeconfig Secrets,
+// so the Cluster controller isn't notified of every change to a Secret in the management cluster.
+func kubeconfigSecrets() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isKubeconfigSecret(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isKubeconfigSecret(e.MetaNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isKubeconfigSecret(e.Meta)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return isKubeconfigSecret(e.Meta)
+		},
+	}
+}
+
+// ignoreClusterStatusUpdates returns a predicate filtering out the updates of Clusters changing only their status,
+// e.g. the ones written by the Cluster controller itself at the end of each reconciliation, which would otherwise
+// trigger another reconciliation right away; the events for other kinds are not filtered.
+func ignoreClusterStatusUpdates() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if _, ok := e.ObjectNew.(*clusterv1.Cluster); !ok {
+				return true
+			}
+			return !isStatusOnlyUpdate(e.MetaOld, e.MetaNew)
+		},
+	}
+}
+
+// isStatusOnlyUpdate returns true if neither the spec, as tracked by the generation, nor the metadata the Cluster
+// controller acts on, e.g. the annotations pausing or forcing reconciliations, differ between the two objects.
+func isStatusOnlyUpdate(oldObj, newObj metav1.Object) bool {
+	if oldObj == nil || newObj == nil {
+		return false
+	}
+	return oldObj.GetGeneration() == newObj.GetGeneration() &&
+		reflect.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) &&
+		reflect.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()) &&
+		reflect.DeepEqual(oldObj.GetFinalizers(), newObj.GetFinalizers()) &&
+		reflect.DeepEqual(oldObj.GetOwnerReferences(), newObj.GetOwnerReferences()) &&
+		oldObj.GetDeletionTimestamp().Equal(newObj.GetDeletionTimestamp())
+}
+
+// isKubeconfigSecret returns true if the object is named as the kubeconfig Secret of the Cluster it is labeled with.
+func isKubeconfigSecret(o metav1.Object) bool {
+	if o == nil {
+		return false
+	}
+	clusterName, ok := o.GetLabels()[clusterv1.ClusterLabelName]
+	return ok && o.GetName() == secret.Name(clusterName, secret.Kubeconfig)
+}
@@ -17,32 +17,70 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
-func (r *ClusterReconciler) reconcilePhase(_ context.Context, cluster *clusterv1.Cluster) {
-	if cluster.Status.Phase == "" {
+func (r *ClusterReconciler) reconcilePhase(ctx context.Context, cluster *clusterv1.Cluster) {
+	// Without references nor Machines there is no way to determine the state of the Cluster.
+	missingReferences := r.hasMissingReferences(ctx, cluster)
+	if missingReferences {
+		conditions.MarkFalse(cluster, clusterv1.ReferencesDefinedCondition, clusterv1.MissingReferencesReason, clusterv1.ConditionSeverityWarning,
+			"Neither Spec.InfrastructureRef nor Spec.ControlPlaneRef are set, and there are no Machines")
+	} else {
+		conditions.Delete(cluster, clusterv1.ReferencesDefinedCondition)
+	}
+
+	// Derive the phase from the Ready condition only, if requested; the Ready condition is summarized
+	// beforehand, so the phase reflects the current state of the other conditions.
+	if r.PhaseFromConditions {
+		r.setReadyCondition(cluster)
+		cluster.Status.SetTypedPhase(phaseFromReadyCondition(cluster))
+		return
+	}
+
+	if cluster.Status.Phase == "" || cluster.Status.GetTypedPhase() == clusterv1.ClusterPhaseUnknown {
 		cluster.Status.SetTypedPhase(clusterv1.ClusterPhasePending)
 	}
 
+	if missingReferences {
+		cluster.Status.SetTypedPhase(clusterv1.ClusterPhaseUnknown)
+	}
+
 	if cluster.Spec.InfrastructureRef != nil {
 		cluster.Status.SetTypedPhase(clusterv1.ClusterPhaseProvisioning)
 	}
@@ -60,6 +98,60 @@ func (r *ClusterReconciler) reconcilePhase(_ context.Context, cluster *clusterv1
 	}
 }
 
+// deletingPhaseReasons are the reasons of the Ready condition mapped to the Deleting phase by phaseFromReadyCondition.
+var deletingPhaseReasons = sets.NewString(
+	clusterv1.InfrastructureDeletingReason,
+	clusterv1.InfrastructureDeletedReason,
+	clusterv1.InfrastructureRecreateFailedReason,
+	clusterv1.ControlPlaneDeletingReason,
+	clusterv1.InfrastructureDeletionPausedReason,
+	clusterv1.NamespaceTerminatingReason,
+)
+
+// phaseFromReadyCondition returns the phase of a Cluster according to its Ready condition only:
+// - Pending, if the Ready condition does not exist;
+// - Provisioned, if the Ready condition is true;
+// - Deleting, if the Ready condition reason documents the deletion of the Cluster or of its references;
+// - Unknown, if the Ready condition reason documents missing references;
+// - Failed, if the Ready condition has severity Error;
+// - Provisioning, otherwise.
+func phaseFromReadyCondition(cluster *clusterv1.Cluster) clusterv1.ClusterPhase {
+	ready := conditions.Get(cluster, clusterv1.ReadyCondition)
+	switch {
+	case ready == nil:
+		return clusterv1.ClusterPhasePending
+	case ready.Status == corev1.ConditionTrue:
+		return clusterv1.ClusterPhaseProvisioned
+	case deletingPhaseReasons.Has(ready.Reason):
+		return clusterv1.ClusterPhaseDeleting
+	case ready.Reason == clusterv1.MissingReferencesReason:
+		return clusterv1.ClusterPhaseUnknown
+	case ready.Severity == clusterv1.ConditionSeverityError:
+		return clusterv1.ClusterPhaseFailed
+	default:
+		return clusterv1.ClusterPhaseProvisioning
+	}
+}
+
+// hasMissingReferences returns true if the Cluster has neither an infrastructure nor a control plane reference,
+// and there are no Machines belonging to it.
+func (r *ClusterReconciler) hasMissingReferences(ctx context.Context, cluster *clusterv1.Cluster) bool {
+	if cluster.Spec.InfrastructureRef != nil || cluster.Spec.ControlPlaneRef != nil {
+		return false
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+	); err != nil {
+		// If Machines can't be listed, do not make assumptions on the state of the Cluster.
+		r.Log.Error(err, "Failed to list Machines", "cluster", cluster.Name, "namespace", cluster.Namespace)
+		return false
+	}
+	return len(machines.Items) == 0
+}
+
 // reconcileExternal handles generic unstructured objects referenced by a Cluster.
 func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) (external.ReconcileOutput, error) {
 	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
@@ -68,12 +160,17 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 		return external.ReconcileOutput{}, err
 	}
 
-	obj, err := external.Get(ctx, r.Client, ref, cluster.Namespace)
+	getRef, namespace, err := r.resolveReference(ctx, cluster, ref)
+	if err != nil {
+		return external.ReconcileOutput{}, err
+	}
+
+	obj, err := r.externalGetter().Get(ctx, r.Client, getRef, namespace)
 	if err != nil {
 		if apierrors.IsNotFound(errors.Cause(err)) {
 			return external.ReconcileOutput{}, errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: 30 * time.Second},
-				"could not find %v %q for Cluster %q in namespace %q, requeuing",
-				ref.GroupVersionKind(), ref.Name, cluster.Name, cluster.Namespace)
+				"could not find %v %q in namespace %q for Cluster %q in namespace %q, requeuing",
+				ref.GroupVersionKind(), ref.Name, namespace, cluster.Name, cluster.Namespace)
 		}
 		return external.ReconcileOutput{}, err
 	}
@@ -81,7 +178,7 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 	// if external ref is paused, return error.
 	if annotations.IsPaused(cluster, obj) {
 		logger.V(3).Info("External object referenced is paused")
-		return external.ReconcileOutput{Paused: true}, nil
+		return external.ReconcileOutput{Result: obj, Paused: true}, nil
 	}
 
 	// Initialize the patch helper.
@@ -90,9 +187,17 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 		return external.ReconcileOutput{}, err
 	}
 
-	// Set external object ControllerReference to the Cluster.
-	if err := controllerutil.SetControllerReference(cluster, obj, r.scheme); err != nil {
-		return external.ReconcileOutput{}, err
+	// Set external object ControllerReference to the Cluster, or an OwnerReference for the infrastructure object.
+	// NOTE: Cross-namespace owner references are not allowed, so objects living in a namespace
+	// other than the Cluster's one are not owned by the Cluster.
+	if obj.GetNamespace() == cluster.Namespace {
+		if identicalReferences(cluster, ref, cluster.Spec.InfrastructureRef) {
+			if err := r.setInfrastructureOwnerReference(cluster, obj); err != nil {
+				return external.ReconcileOutput{}, err
+			}
+		} else if err := controllerutil.SetControllerReference(cluster, obj, r.scheme); err != nil {
+			return external.ReconcileOutput{}, err
+		}
 	}
 
 	// Set the Cluster label.
@@ -118,41 +223,428 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 	if err != nil {
 		return external.ReconcileOutput{}, err
 	}
+	failureMessagePrefix := fmt.Sprintf("Failure detected from referenced resource %v with name %q: ", obj.GroupVersionKind(), obj.GetName())
 	if failureReason != "" {
 		clusterStatusError := capierrors.ClusterStatusError(failureReason)
 		cluster.Status.FailureReason = &clusterStatusError
 	}
 	if failureMessage != "" {
-		cluster.Status.FailureMessage = pointer.StringPtr(
-			fmt.Sprintf("Failure detected from referenced resource %v with name %q: %s",
-				obj.GroupVersionKind(), obj.GetName(), failureMessage),
-		)
+		cluster.Status.FailureMessage = pointer.StringPtr(failureMessagePrefix + failureMessage)
+	}
+
+	// Clear the failure reason and message once the object they have been detected from recovers, if requested;
+	// failures are attributed to an object through the failure message, so failures without a message are retained.
+	if r.ClearFailuresOnRecovery && failureReason == "" && failureMessage == "" &&
+		cluster.Status.FailureMessage != nil && strings.HasPrefix(*cluster.Status.FailureMessage, failureMessagePrefix) {
+		logger.Info("Clearing failure after recovery of referenced resource", "kind", obj.GetKind(), "name", obj.GetName())
+		cluster.Status.FailureReason = nil
+		cluster.Status.FailureMessage = nil
 	}
 
 	return external.ReconcileOutput{Result: obj}, nil
 }
 
-// reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Cluster.
+// setInfrastructureOwnerReference sets the Cluster owner reference on the infrastructure object of a Cluster, so the
+// object gets garbage collected even if the Cluster is force-deleted. The Cluster does not become the controller of
+// the object, which might already be controlled by another controller, e.g. the provider; objects managed by an
+// external system are not owned by the Cluster.
+func (r *ClusterReconciler) setInfrastructureOwnerReference(cluster *clusterv1.Cluster, obj *unstructured.Unstructured) error {
+	if annotations.IsExternallyManaged(obj) {
+		return nil
+	}
+	// Retain the owner reference set by previous versions, which made the Cluster the controller of the object.
+	if !util.IsOwnedByObject(obj, cluster) {
+		if err := controllerutil.SetOwnerReference(cluster, obj, r.scheme); err != nil {
+			return err
+		}
+	}
+	refs := obj.GetOwnerReferences()
+	if blockClusterOwnerDeletion(refs, cluster) > 0 {
+		obj.SetOwnerReferences(refs)
+	}
+	return nil
+}
+
+// recreateInfrastructure recreates the deleted infrastructure object of a Cluster from the template named by the
+// InfrastructureTemplateAnnotation, if RecreateDeletedInfrastructure is set.
+func (r *ClusterReconciler) recreateInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) error {
+	templateName, ok := cluster.Annotations[clusterv1.InfrastructureTemplateAnnotation]
+	if !r.RecreateDeletedInfrastructure || !ok {
+		return nil
+	}
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	ref := cluster.Spec.InfrastructureRef
+	templateRef := &corev1.ObjectReference{
+		APIVersion: ref.APIVersion,
+		Kind:       ref.Kind + external.TemplateSuffix,
+		Name:       templateName,
+		Namespace:  cluster.Namespace,
+	}
+	template, err := r.externalGetter().Get(ctx, r.Client, templateRef, cluster.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s %q to recreate infrastructure for Cluster %q in namespace %q",
+			templateRef.Kind, templateRef.Name, cluster.Name, cluster.Namespace)
+	}
+
+	infraConfig, err := external.GenerateTemplate(&external.GenerateTemplateInput{
+		Template:    template,
+		Namespace:   cluster.Namespace,
+		ClusterName: cluster.Name,
+		OwnerRef: &metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate infrastructure for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+	// The Cluster keeps referencing the infrastructure object by name, so the object is recreated with the same name.
+	infraConfig.SetName(ref.Name)
+
+	logger.Info("Recreating infrastructure object from template", "kind", ref.Kind, "name", ref.Name, "template", templateName)
+	if err := r.Client.Create(ctx, infraConfig); err != nil {
+		// The infrastructure object has already been recreated, e.g. by its controller.
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to recreate %s %q for Cluster %q in namespace %q", ref.Kind, ref.Name, cluster.Name, cluster.Namespace)
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "InfrastructureRecreated",
+		"Infrastructure %s %q has been recreated from %s %q", ref.Kind, ref.Name, templateRef.Kind, templateName)
+	return nil
+}
+
+// recreateInfrastructureRetryAfter returns how long to wait before attempting again to recreate the deleted
+// infrastructure object of a Cluster, if the last attempt failed, as reported by the InfrastructureReadyCondition.
+func (r *ClusterReconciler) recreateInfrastructureRetryAfter(cluster *clusterv1.Cluster) time.Duration {
+	condition := conditions.Get(cluster, clusterv1.InfrastructureReadyCondition)
+	if condition == nil || condition.Reason != clusterv1.InfrastructureRecreateFailedReason {
+		return 0
+	}
+	return time.Until(condition.LastTransitionTime.Add(r.infrastructureNotFoundRequeueAfter()))
+}
+
+// resolveReference returns the reference and the namespace to be used for getting the object referenced by a Cluster,
+// looking up the CRD of the referenced kind once: the reference uses the API version defined by the
+// InfraAPIVersionAnnotation, if any, or else the storage version of the CRD for kinds not registered in the scheme.
+func (r *ClusterReconciler) resolveReference(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) (*corev1.ObjectReference, string, error) {
+	crd, err := util.GetCRDWithContract(ctx, r.Client, ref.GroupVersionKind(), clusterv1.GroupVersion.String())
+	if err != nil {
+		// If the CRD can't be retrieved, use the reference as is; any error is going to be surfaced
+		// when getting the referenced object.
+		r.Log.V(4).Info("Failed to get CRD for reference", "kind", ref.Kind, "error", err.Error())
+		crd = nil
+	}
+
+	getRef, err := r.overrideInfrastructureAPIVersion(cluster, ref, crd)
+	if err != nil {
+		return nil, "", err
+	}
+	if getRef == ref && crd != nil && (r.scheme == nil || !r.scheme.Recognizes(ref.GroupVersionKind())) {
+		getRef = external.StorageVersionReference(crd, ref)
+	}
+	return getRef, r.refNamespace(cluster, ref, crd), nil
+}
+
+// getExternal gets the object referenced by a Cluster, as resolved by resolveReference.
+func (r *ClusterReconciler) getExternal(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	getRef, namespace, err := r.resolveReference(ctx, cluster, ref)
+	if err != nil {
+		return nil, err
+	}
+	return r.externalGetter().Get(ctx, r.Client, getRef, namespace)
+}
+
+// overrideInfrastructureAPIVersion returns a copy of the given reference using the API version defined by the
+// InfraAPIVersionAnnotation, if any, when the reference is the infrastructure reference of the Cluster;
+// the API version must be served by the given CRD of the referenced kind.
+func (r *ClusterReconciler) overrideInfrastructureAPIVersion(cluster *clusterv1.Cluster, ref *corev1.ObjectReference, crd *apiextensionsv1.CustomResourceDefinition) (*corev1.ObjectReference, error) {
+	apiVersion, ok := cluster.Annotations[clusterv1.InfraAPIVersionAnnotation]
+	if !ok || ref != cluster.Spec.InfrastructureRef {
+		return ref, nil
+	}
+
+	gvk := ref.GroupVersionKind()
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || gv.Version == "" || gv.Group != gvk.Group {
+		return nil, errors.Errorf("invalid value %q for annotation %q on Cluster %q in namespace %q: must be an API version in group %q",
+			apiVersion, clusterv1.InfraAPIVersionAnnotation, cluster.Name, cluster.Namespace, gvk.Group)
+	}
+
+	if crd == nil {
+		return nil, errors.Errorf("cannot validate annotation %q on Cluster %q in namespace %q: CustomResourceDefinition for %v not found",
+			clusterv1.InfraAPIVersionAnnotation, cluster.Name, cluster.Namespace, gvk)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Name == gv.Version && version.Served {
+			overridden := ref.DeepCopy()
+			overridden.APIVersion = gv.String()
+			return overridden, nil
+		}
+	}
+	return nil, errors.Errorf("version %q of %s is not served, as required by annotation %q on Cluster %q in namespace %q",
+		gv.Version, crd.Name, clusterv1.InfraAPIVersionAnnotation, cluster.Name, cluster.Namespace)
+}
+
+// refNamespace returns the namespace of the object referenced by a Cluster, defaulting to the Cluster's namespace
+// when the reference does not define one; an empty namespace is returned for cluster-scoped kinds, as declared
+// by the scope of the given CRD, if known.
+func (r *ClusterReconciler) refNamespace(cluster *clusterv1.Cluster, ref *corev1.ObjectReference, crd *apiextensionsv1.CustomResourceDefinition) string {
+	if crd != nil && crd.Spec.Scope == apiextensionsv1.ClusterScoped {
+		return ""
+	}
+	return refNamespaceOrDefault(cluster, ref)
+}
+
+// reconcileReferences reports in the DistinctReferencesCondition if the control plane and the infrastructure
+// references of a Cluster point to the same object.
+func (r *ClusterReconciler) reconcileReferences(_ context.Context, cluster *clusterv1.Cluster) error {
+	if identicalReferences(cluster, cluster.Spec.ControlPlaneRef, cluster.Spec.InfrastructureRef) {
+		conditions.MarkFalse(cluster, clusterv1.DistinctReferencesCondition, clusterv1.IdenticalReferencesReason, clusterv1.ConditionSeverityError,
+			"Spec.ControlPlaneRef and Spec.InfrastructureRef both point to %s %q", cluster.Spec.ControlPlaneRef.Kind, cluster.Spec.ControlPlaneRef.Name)
+		return nil
+	}
+	conditions.Delete(cluster, clusterv1.DistinctReferencesCondition)
+	return nil
+}
+
+// reconcileReferencedKinds reports in the ReferencedKindsValidCondition if the control plane or the infrastructure
+// reference of a Cluster points to a template instead of an instance, if ValidateReferencedKinds is set.
+func (r *ClusterReconciler) reconcileReferencedKinds(_ context.Context, cluster *clusterv1.Cluster) error {
+	if !r.ValidateReferencedKinds {
+		return nil
+	}
+
+	for _, ref := range []struct {
+		field string
+		ref   *corev1.ObjectReference
+	}{
+		{"Spec.ControlPlaneRef", cluster.Spec.ControlPlaneRef},
+		{"Spec.InfrastructureRef", cluster.Spec.InfrastructureRef},
+	} {
+		if ref.ref != nil && strings.HasSuffix(ref.ref.Kind, external.TemplateSuffix) {
+			conditions.MarkFalse(cluster, clusterv1.ReferencedKindsValidCondition, clusterv1.TemplateReferencedReason, clusterv1.ConditionSeverityError,
+				"%s points to the template %s %q instead of an instance", ref.field, ref.ref.Kind, ref.ref.Name)
+			return nil
+		}
+	}
+	conditions.Delete(cluster, clusterv1.ReferencedKindsValidCondition)
+	return nil
+}
+
+// identicalReferences returns true if the given references of a Cluster point to the same object,
+// regardless of the API version.
+func identicalReferences(cluster *clusterv1.Cluster, a, b *corev1.ObjectReference) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.GroupVersionKind().GroupKind() == b.GroupVersionKind().GroupKind() &&
+		a.Name == b.Name &&
+		refNamespaceOrDefault(cluster, a) == refNamespaceOrDefault(cluster, b)
+}
+
+// refNamespaceOrDefault returns the namespace of a reference, defaulting to the Cluster's namespace.
+func refNamespaceOrDefault(cluster *clusterv1.Cluster, ref *corev1.ObjectReference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return cluster.Namespace
+}
+
+// adoptInfrastructure sets the InfrastructureRef of a Cluster to the pre-existing infrastructure object named by
+// the AdoptInfrastructureAnnotation, if any, looking it up among the infrastructure kinds satisfying the Cluster API contract.
+// The adopted object is then owned by the Cluster and its conditions mirrored as for any other infrastructure object.
+func (r *ClusterReconciler) adoptInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	name, ok := cluster.Annotations[clusterv1.AdoptInfrastructureAnnotation]
+	if !ok || cluster.Spec.InfrastructureRef != nil {
+		return nil
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := r.Client.List(ctx, crds, client.HasLabels{clusterv1.GroupVersion.String()}); err != nil {
+		return errors.Wrapf(err, "failed to list CustomResourceDefinitions to adopt infrastructure %q for Cluster %q in namespace %q",
+			name, cluster.Name, cluster.Namespace)
+	}
+
+	var refs []*corev1.ObjectReference
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		// Templates are not infrastructure objects, so they can't be adopted.
+		if crd.Spec.Group != infrastructureGroup || strings.HasSuffix(crd.Spec.Names.Kind, "Template") {
+			continue
+		}
+		for _, version := range crd.Spec.Versions {
+			if !version.Storage {
+				continue
+			}
+			ref := &corev1.ObjectReference{
+				APIVersion: schema.GroupVersion{Group: crd.Spec.Group, Version: version.Name}.String(),
+				Kind:       crd.Spec.Names.Kind,
+				Name:       name,
+				Namespace:  cluster.Namespace,
+			}
+			if _, err := r.externalGetter().Get(ctx, r.Client, ref, cluster.Namespace); err != nil {
+				if apierrors.IsNotFound(errors.Cause(err)) {
+					continue
+				}
+				return err
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	switch len(refs) {
+	case 0:
+		conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason,
+			clusterv1.ConditionSeverityInfo, "Waiting for infrastructure %q to be adopted", name)
+		return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: r.infrastructureNotFoundRequeueAfter()},
+			"infrastructure %q to be adopted by Cluster %q in namespace %q not found, requeuing", name, cluster.Name, cluster.Namespace)
+	case 1:
+		logger.Info("Adopting infrastructure object", "kind", refs[0].Kind, "name", name)
+		cluster.Spec.InfrastructureRef = refs[0]
+		return nil
+	default:
+		kinds := make([]string, len(refs))
+		for i := range refs {
+			kinds[i] = refs[i].Kind
+		}
+		return errors.Errorf("cannot adopt infrastructure %q for Cluster %q in namespace %q: found more than one object with this name, of kinds %s",
+			name, cluster.Name, cluster.Namespace, strings.Join(kinds, ","))
+	}
+}
+
+// clusterReadiness is a snapshot of the objects referenced by a Cluster, read once per reconciliation and shared by
+// the phases deriving the Cluster status and conditions from them, which in turn drive reconcilePhase.
+type clusterReadiness struct {
+	infrastructure    external.ReconcileOutput
+	infrastructureErr error
+	controlPlane      external.ReconcileOutput
+	controlPlaneErr   error
+}
+
+// computeReadiness reads the infrastructure and the control plane objects referenced by a Cluster through the generic
+// external reconciler, once each; if both references point to the same object, it is read only once.
+func (r *ClusterReconciler) computeReadiness(ctx context.Context, cluster *clusterv1.Cluster) *clusterReadiness {
+	readiness := &clusterReadiness{}
+	if cluster.Spec.InfrastructureRef != nil {
+		readiness.infrastructure, readiness.infrastructureErr = r.reconcileExternal(ctx, cluster, cluster.Spec.InfrastructureRef)
+	}
+	if cluster.Spec.ControlPlaneRef != nil {
+		if identicalReferences(cluster, cluster.Spec.ControlPlaneRef, cluster.Spec.InfrastructureRef) {
+			readiness.controlPlane, readiness.controlPlaneErr = readiness.infrastructure, readiness.infrastructureErr
+		} else {
+			readiness.controlPlane, readiness.controlPlaneErr = r.reconcileExternal(ctx, cluster, cluster.Spec.ControlPlaneRef)
+		}
+	}
+	return readiness
+}
+
+// reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Cluster, reading it on its own.
 func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) error {
+	if err := r.adoptInfrastructure(ctx, cluster); err != nil {
+		return err
+	}
+	return r.reconcileInfrastructureReadiness(ctx, cluster, r.computeReadiness(ctx, cluster))
+}
+
+// reconcileInfrastructureReadiness reconciles the Spec.InfrastructureRef object on a Cluster, as read in the given snapshot.
+func (r *ClusterReconciler) reconcileInfrastructureReadiness(ctx context.Context, cluster *clusterv1.Cluster, readiness *clusterReadiness) error {
 	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
 
 	if cluster.Spec.InfrastructureRef == nil {
 		return nil
 	}
 
-	// Call generic external reconciler.
-	infraReconcileResult, err := r.reconcileExternal(ctx, cluster, cluster.Spec.InfrastructureRef)
+	infraReconcileResult, err := readiness.infrastructure, readiness.infrastructureErr
 	if err != nil {
+		// reconcileExternal asks to requeue when the infrastructure object does not exist yet, e.g. because
+		// its controller did not create it yet; wait for it using the configured backoff.
+		if _, ok := errors.Cause(err).(capierrors.HasRequeueAfterError); ok {
+			ref := cluster.Spec.InfrastructureRef
+
+			// If the infrastructure was already provisioned, the infrastructure object has been deleted out of band,
+			// so the Cluster can't be considered provisioned anymore.
+			reason := conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)
+			if cluster.Status.InfrastructureReady || reason == clusterv1.InfrastructureDeletedReason || reason == clusterv1.InfrastructureRecreateFailedReason {
+				if cluster.Status.InfrastructureReady {
+					// Tolerate the infrastructure object briefly missing, e.g. while being recreated by its controller.
+					if notFound := r.infrastructureNotFound.record(cluster.UID); notFound < r.infrastructureNotFoundThreshold() {
+						logger.Info("Infrastructure object not found, waiting before reporting it as deleted",
+							"kind", ref.Kind, "name", ref.Name, "notFound", notFound)
+						return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: r.infrastructureNotFoundRequeueAfter()},
+							"infrastructure %s %q for Cluster %q in namespace %q not found, requeuing", ref.Kind, ref.Name, cluster.Name, cluster.Namespace)
+					}
+					r.infrastructureNotFound.forget(cluster.UID)
+
+					logger.Info("Infrastructure object has been deleted while the Cluster is not", "kind", ref.Kind, "name", ref.Name)
+					r.recorder.Eventf(cluster, corev1.EventTypeWarning, "InfrastructureDeleted",
+						"Infrastructure %s %q has been deleted while the Cluster is not", ref.Kind, ref.Name)
+				}
+				cluster.Status.InfrastructureReady = false
+
+				// Failed attempts at recreating the infrastructure object are retried only once the backoff
+				// since the last attempt, as recorded by the InfrastructureReadyCondition, has elapsed.
+				if retryAfter := r.recreateInfrastructureRetryAfter(cluster); retryAfter > 0 {
+					return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: retryAfter},
+						"infrastructure %s %q for Cluster %q in namespace %q could not be recreated, requeuing", ref.Kind, ref.Name, cluster.Name, cluster.Namespace)
+				}
+				conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletedReason,
+					clusterv1.ConditionSeverityWarning, "%s %q has been deleted", ref.Kind, ref.Name)
+				if err := r.recreateInfrastructure(ctx, cluster); err != nil {
+					logger.Error(err, "Failed to recreate infrastructure object", "kind", ref.Kind, "name", ref.Name)
+					conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureRecreateFailedReason,
+						clusterv1.ConditionSeverityWarning, "%s %q has been deleted and could not be recreated: %v", ref.Kind, ref.Name, err)
+				}
+				return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: r.infrastructureNotFoundRequeueAfter()},
+					"infrastructure %s %q for Cluster %q in namespace %q has been deleted, requeuing", ref.Kind, ref.Name, cluster.Name, cluster.Namespace)
+			}
+
+			conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason,
+				clusterv1.ConditionSeverityInfo, "Waiting for %s %q to be created", ref.Kind, ref.Name)
+			return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: r.infrastructureNotFoundRequeueAfter()},
+				"infrastructure %s %q for Cluster %q in namespace %q not found, requeuing", ref.Kind, ref.Name, cluster.Name, cluster.Namespace)
+		}
 		return err
 	}
-	// if the external object is paused, return without any further processing
+	r.infrastructureNotFound.forget(cluster.UID)
+	infraConfig := infraReconcileResult.Result
+
+	// Surface the external controller managing the infrastructure object, if any.
+	if annotations.IsExternallyManaged(infraConfig) {
+		conditions.MarkFalse(cluster, clusterv1.InfrastructureManagedByCondition, clusterv1.ExternallyManagedReason,
+			clusterv1.ConditionSeverityInfo, "%s %q is managed by %q", infraConfig.GetKind(), infraConfig.GetName(),
+			infraConfig.GetAnnotations()[clusterv1.ManagedByAnnotation])
+	} else {
+		conditions.Delete(cluster, clusterv1.InfrastructureManagedByCondition)
+	}
+
+	// If the external object is paused, do not take any further action on it,
+	// but still surface its current state in the InfrastructureReadyCondition.
 	if infraReconcileResult.Paused {
+		ready, err := external.IsReady(infraConfig)
+		if err != nil {
+			return err
+		}
+		r.mirrorReadyCondition(cluster, clusterv1.InfrastructureReadyCondition, infraConfig, ready, clusterv1.WaitingForInfrastructureFallbackReason)
 		return nil
 	}
-	infraConfig := infraReconcileResult.Result
 
-	// There's no need to go any further if the Cluster is marked for deletion.
+	// There's no need to go any further if the infrastructure object is marked for deletion.
+	// If the Cluster is not being deleted, the infrastructure object has been deleted out of band,
+	// so its state can't be trusted anymore.
 	if !infraConfig.GetDeletionTimestamp().IsZero() {
+		if cluster.DeletionTimestamp.IsZero() {
+			logger.Info("Infrastructure object is being deleted while the Cluster is not", "kind", infraConfig.GetKind(), "name", infraConfig.GetName())
+			conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletingReason,
+				clusterv1.ConditionSeverityWarning, "%s %q is being deleted", infraConfig.GetKind(), infraConfig.GetName())
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "InfrastructureDeleting",
+				"Infrastructure %s %q is being deleted while the Cluster is not", infraConfig.GetKind(), infraConfig.GetName())
+		}
 		return nil
 	}
 
@@ -162,8 +654,31 @@ func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster
 		return err
 	}
 	cluster.Status.InfrastructureReady = ready
+
+	// Report a summary of current status of the infrastructure object defined for this cluster.
+	r.mirrorReadyCondition(cluster, clusterv1.InfrastructureReadyCondition, infraConfig, ready, clusterv1.WaitingForInfrastructureFallbackReason)
+
 	if !ready {
 		logger.V(3).Info("Infrastructure provider is not ready yet")
+
+		// Surface the infrastructure object not becoming ready within the configured timeout, if any.
+		timeoutRequeueAfter := r.reconcileInfrastructureReadyTimeout(cluster, infraConfig)
+
+		// Honor the retry hint of the infrastructure provider, if any, e.g. when it is rate-limited.
+		retryAfter, err := external.RetryAfter(infraConfig)
+		if err != nil {
+			return err
+		}
+		if retryAfter > 0 {
+			return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: retryAfter},
+				"infrastructure %s %q for Cluster %q in namespace %q is not ready, retrying after %s",
+				infraConfig.GetKind(), infraConfig.GetName(), cluster.Name, cluster.Namespace, retryAfter)
+		}
+		if timeoutRequeueAfter > 0 {
+			return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: timeoutRequeueAfter},
+				"infrastructure %s %q for Cluster %q in namespace %q is not ready, requeuing",
+				infraConfig.GetKind(), infraConfig.GetName(), cluster.Name, cluster.Namespace)
+		}
 		return nil
 	}
 
@@ -173,6 +688,8 @@ func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster
 			return errors.Wrapf(err, "failed to retrieve Spec.ControlPlaneEndpoint from infrastructure provider for Cluster %q in namespace %q",
 				cluster.Name, cluster.Namespace)
 		}
+	} else if err := r.reconcileControlPlaneEndpointDrift(cluster, infraConfig); err != nil {
+		return err
 	}
 
 	// Get and parse Status.FailureDomains from the infrastructure provider.
@@ -184,15 +701,74 @@ func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster
 	return nil
 }
 
-// reconcileControlPlane reconciles the Spec.ControlPlaneRef object on a Cluster.
+// reconcileInfrastructureReadyTimeout reports in the InfrastructureReadyCondition an infrastructure object which is not
+// ready after the InfrastructureReadyTimeout of the Cluster, if any, has elapsed since the Cluster creation.
+// It returns how long to wait before checking again, i.e. the time left before the timeout, if not exceeded yet.
+func (r *ClusterReconciler) reconcileInfrastructureReadyTimeout(cluster *clusterv1.Cluster, infraConfig *unstructured.Unstructured) time.Duration {
+	timeout := cluster.Spec.InfrastructureReadyTimeout
+	if timeout == nil || timeout.Duration <= 0 {
+		return 0
+	}
+
+	if remaining := timeout.Duration - r.now().Sub(cluster.CreationTimestamp.Time); remaining > 0 {
+		return remaining
+	}
+
+	conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.TimedOutReason, clusterv1.ConditionSeverityWarning,
+		"%s %q is not ready after %s", infraConfig.GetKind(), infraConfig.GetName(), timeout.Duration)
+	return infrastructureReadyTimedOutRequeueAfter
+}
+
+// reconcileControlPlaneEndpointDrift compares the control plane endpoint of a Cluster with the one currently exposed
+// by its infrastructure object, and reports any difference in the ControlPlaneEndpointInSyncCondition.
+// NOTE: The Cluster endpoint is never overwritten once set.
+func (r *ClusterReconciler) reconcileControlPlaneEndpointDrift(cluster *clusterv1.Cluster, infraConfig *unstructured.Unstructured) error {
+	infraEndpoint, err := external.GetControlPlaneEndpoint(infraConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve Spec.ControlPlaneEndpoint from infrastructure provider for Cluster %q in namespace %q",
+			cluster.Name, cluster.Namespace)
+	}
+	if infraEndpoint.IsZero() {
+		return nil
+	}
+
+	if infraEndpoint != cluster.Spec.ControlPlaneEndpoint {
+		if !conditions.IsFalse(cluster, clusterv1.ControlPlaneEndpointInSyncCondition) {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "ControlPlaneEndpointDrift",
+				"Control plane endpoint %s differs from %s exposed by %s %q",
+				cluster.Spec.ControlPlaneEndpoint.String(), infraEndpoint.String(), infraConfig.GetKind(), infraConfig.GetName())
+		}
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneEndpointInSyncCondition, clusterv1.ControlPlaneEndpointDriftedReason,
+			clusterv1.ConditionSeverityWarning, "%s %q exposes endpoint %s", infraConfig.GetKind(), infraConfig.GetName(), infraEndpoint.String())
+		return nil
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneEndpointInSyncCondition)
+	return nil
+}
+
+// reconcileControlPlane reconciles the Spec.ControlPlaneRef object on a Cluster, reading it on its own.
 func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, cluster *clusterv1.Cluster) error {
+	return r.reconcileControlPlaneReadiness(ctx, cluster, r.computeReadiness(ctx, cluster))
+}
+
+// reconcileControlPlaneReadiness reconciles the Spec.ControlPlaneRef object on a Cluster, as read in the given snapshot.
+func (r *ClusterReconciler) reconcileControlPlaneReadiness(ctx context.Context, cluster *clusterv1.Cluster, readiness *clusterReadiness) error {
 	if cluster.Spec.ControlPlaneRef == nil {
-		return nil
+		return r.reconcileControlPlaneMachines(ctx, cluster)
 	}
 
-	// Call generic external reconciler.
-	controlPlaneReconcileResult, err := r.reconcileExternal(ctx, cluster, cluster.Spec.ControlPlaneRef)
+	controlPlaneReconcileResult, err := readiness.controlPlane, readiness.controlPlaneErr
 	if err != nil {
+		// Point out the missing provider instead of surfacing a generic error about an unknown kind.
+		if meta.IsNoMatchError(errors.Cause(err)) {
+			ref := cluster.Spec.ControlPlaneRef
+			conditions.MarkFalse(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.ProviderNotInstalledReason, clusterv1.ConditionSeverityError,
+				"Kind %s in group %s is not served by the API server, the control plane provider might not be installed",
+				ref.Kind, ref.GroupVersionKind().Group)
+			return errors.Wrapf(err, "control plane provider for kind %s referenced by Cluster %q in namespace %q is not installed",
+				ref.Kind, cluster.Name, cluster.Namespace)
+		}
 		return err
 	}
 	// if the external object is paused, return without any further processing
@@ -223,14 +799,86 @@ func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, cluster *
 	}
 	cluster.Status.ControlPlaneReady = ready
 
+	// Report a summary of current status of the control plane object defined for this cluster.
+	r.mirrorReadyCondition(cluster, clusterv1.ControlPlaneReadyCondition, controlPlaneConfig, ready, clusterv1.WaitingForControlPlaneFallbackReason)
+
 	return nil
 }
 
-func (r *ClusterReconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1.Cluster) error {
-	if cluster.Spec.ControlPlaneEndpoint.IsZero() {
+// reconcileControlPlaneMachines reports the ControlPlaneReadyCondition for a Cluster without a control plane provider,
+// deriving it from the readiness of the control plane Machines.
+func (r *ClusterReconciler) reconcileControlPlaneMachines(ctx context.Context, cluster *clusterv1.Cluster) error {
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return errors.Wrapf(err, "failed to list Machines for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+
+	controlPlaneMachines, _ := splitMachineList(machines)
+
+	total, ready := 0, 0
+	for i := range controlPlaneMachines.Items {
+		m := &controlPlaneMachines.Items[i]
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		total++
+		if conditions.IsTrue(m, clusterv1.ReadyCondition) {
+			ready++
+		}
+	}
+
+	if total > 0 && ready == total {
+		conditions.MarkTrue(cluster, clusterv1.ControlPlaneReadyCondition)
+		return nil
+	}
+	conditions.MarkFalse(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.WaitingForControlPlaneMachinesReason,
+		clusterv1.ConditionSeverityInfo, "%d of %d control plane Machines are ready", ready, total)
+	return nil
+}
+
+// reconcileUnmanagedControlPlaneMachines reports, for a Cluster with a control plane provider, if there are
+// control plane Machines directly owned by the Cluster, and thus not managed by the control plane provider.
+func (r *ClusterReconciler) reconcileUnmanagedControlPlaneMachines(ctx context.Context, cluster *clusterv1.Cluster) error {
+	if cluster.Spec.ControlPlaneRef == nil {
 		return nil
 	}
 
+	machines, err := getActiveMachinesInCluster(ctx, r.Client, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	var unmanaged []string
+	for _, m := range machines {
+		if util.IsControlPlaneMachine(m) && util.IsOwnedByObject(m, cluster) {
+			unmanaged = append(unmanaged, m.Name)
+		}
+	}
+
+	if len(unmanaged) > 0 {
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneMachinesManagedCondition, clusterv1.UnmanagedControlPlaneMachinesReason,
+			clusterv1.ConditionSeverityWarning, "Control plane Machines %s are owned by the Cluster instead of %s %q",
+			strings.Join(unmanaged, ", "), cluster.Spec.ControlPlaneRef.Kind, cluster.Spec.ControlPlaneRef.Name)
+		return nil
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneMachinesManagedCondition)
+	return nil
+}
+
+func (r *ClusterReconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	// Export the non-secret parts of the Kubeconfig, if requested, regardless of who manages the Kubeconfig.
+	if r.ExportKubeconfigConfigMap && cluster.Spec.ControlPlaneEndpoint.Host != "" {
+		if err := r.reconcileKubeconfigConfigMap(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
 	// Do not generate the Kubeconfig if there is a ControlPlaneRef, since the Control Plane provider is
 	// responsible for the management of the Kubeconfig. We continue to manage it here only for backward
 	// compatibility when a Control Plane provider is not in use.
@@ -238,7 +886,15 @@ func (r *ClusterReconciler) reconcileKubeconfig(ctx context.Context, cluster *cl
 		return nil
 	}
 
-	_, err := secret.Get(ctx, r.Client, util.ObjectKey(cluster), secret.Kubeconfig)
+	// The Kubeconfig can't be generated until the control plane endpoint is known.
+	if cluster.Spec.ControlPlaneEndpoint.Host == "" {
+		logger.V(4).Info("Skipping Kubeconfig generation, control plane endpoint is not set yet")
+		return errors.Wrapf(&capierrors.RequeueAfterError{RequeueAfter: kubeconfigEndpointRequeueAfter},
+			"control plane endpoint for Cluster %q in namespace %q is not set yet, requeuing",
+			cluster.Name, cluster.Namespace)
+	}
+
+	configSecret, err := secret.Get(ctx, r.Client, util.ObjectKey(cluster), secret.Kubeconfig)
 	switch {
 	case apierrors.IsNotFound(err):
 		if err := kubeconfig.CreateSecret(ctx, r.Client, cluster); err != nil {
@@ -251,7 +907,619 @@ func (r *ClusterReconciler) reconcileKubeconfig(ctx context.Context, cluster *cl
 		}
 	case err != nil:
 		return errors.Wrapf(err, "failed to retrieve Kubeconfig Secret for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	default:
+		return r.repairKubeconfig(ctx, cluster, configSecret)
 	}
 
 	return nil
 }
+
+// repairKubeconfig regenerates the given Kubeconfig Secret if it has been edited so that it no longer gives
+// access to the Cluster, e.g. if it points to another endpoint; the Secret is left untouched if the cluster CA
+// can't be found, given that a new Kubeconfig can't be generated anyway.
+func (r *ClusterReconciler) repairKubeconfig(ctx context.Context, cluster *clusterv1.Cluster, configSecret *corev1.Secret) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	endpoint := cluster.Spec.ControlPlaneEndpoint.String()
+	verifyErr := kubeconfig.VerifySecret(ctx, r.Client, util.ObjectKey(cluster), endpoint, configSecret)
+	if verifyErr == nil {
+		return nil
+	}
+	if verifyErr == kubeconfig.ErrDependentCertificateNotFound {
+		logger.V(4).Info("Skipping Kubeconfig verification, cluster CA not found", "secret", configSecret.Name)
+		return nil
+	}
+
+	logger.Info("Regenerating Kubeconfig Secret", "secret", configSecret.Name, "reason", verifyErr.Error())
+	if err := kubeconfig.RegenerateSecret(ctx, r.Client, util.ObjectKey(cluster), endpoint, configSecret); err != nil {
+		return errors.Wrapf(err, "failed to regenerate Kubeconfig Secret for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+	return nil
+}
+
+// kubeconfigConfigMapName returns the name of the ConfigMap exporting the non-secret parts of the Kubeconfig of a Cluster.
+func kubeconfigConfigMapName(clusterName string) string {
+	return fmt.Sprintf("%s-kubeconfig-info", clusterName)
+}
+
+// reconcileKubeconfigConfigMap creates or updates the ConfigMap, owned by the Cluster, exporting the server URL
+// and the cluster name of the Kubeconfig of a Cluster; no credentials are included.
+func (r *ClusterReconciler) reconcileKubeconfigConfigMap(ctx context.Context, cluster *clusterv1.Cluster) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubeconfigConfigMapName(cluster.Name),
+			Namespace: cluster.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterLabelName] = cluster.Name
+		configMap.OwnerReferences = util.EnsureOwnerRef(configMap.OwnerReferences, metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+		})
+		configMap.Data = map[string]string{
+			"server":      fmt.Sprintf("https://%s", cluster.Spec.ControlPlaneEndpoint.String()),
+			"clusterName": cluster.Name,
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to export Kubeconfig ConfigMap for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+	return nil
+}
+
+// reconcileClusterLabel sets the ClusterLabelName label on the Cluster itself, so selectors targeting all
+// the objects of a cluster also match the Cluster; a value already set by users is preserved.
+func (r *ClusterReconciler) reconcileClusterLabel(_ context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	labels := cluster.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	if value, ok := labels[clusterv1.ClusterLabelName]; ok {
+		if value != cluster.Name {
+			logger.V(4).Info("Cluster label already set to a different value, skipping", "value", value)
+		}
+		return nil
+	}
+	labels[clusterv1.ClusterLabelName] = cluster.Name
+	cluster.SetLabels(labels)
+	return nil
+}
+
+// reconcileLegacyLabels adds the ClusterLabelName label to the descendants of a Cluster only carrying the
+// LegacyClusterLabelName label, so they are taken into account e.g. during deletion.
+// NOTE: This is an opt-in behavior, enabled by setting the NormalizeLegacyLabelsAnnotation on the Cluster.
+func (r *ClusterReconciler) reconcileLegacyLabels(ctx context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	if _, ok := cluster.Annotations[clusterv1.NormalizeLegacyLabelsAnnotation]; !ok {
+		return nil
+	}
+
+	lists := []runtime.Object{
+		&clusterv1.MachineDeploymentList{},
+		&clusterv1.MachineSetList{},
+		&clusterv1.MachineList{},
+	}
+	for _, list := range lists {
+		if err := r.Client.List(ctx, list,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{clusterv1.LegacyClusterLabelName: cluster.Name},
+		); err != nil {
+			return errors.Wrapf(err, "failed to list descendants with legacy labels for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+		}
+
+		if err := meta.EachListItem(list, func(obj runtime.Object) error {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			if _, ok := accessor.GetLabels()[clusterv1.ClusterLabelName]; ok {
+				return nil
+			}
+
+			patchHelper, err := patch.NewHelper(obj, r.Client)
+			if err != nil {
+				return err
+			}
+			labels := accessor.GetLabels()
+			labels[clusterv1.ClusterLabelName] = cluster.Name
+			accessor.SetLabels(labels)
+
+			logger.Info("Adding cluster label to descendant with legacy labels", "kind", fmt.Sprintf("%T", obj), "name", accessor.GetName())
+			return patchHelper.Patch(ctx, obj)
+		}); err != nil {
+			return errors.Wrapf(err, "failed to normalize labels on descendants of Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+		}
+	}
+	return nil
+}
+
+// reconcileDescendantOwnerReferences repairs the owner references of the descendants of a Cluster.
+func (r *ClusterReconciler) reconcileDescendantOwnerReferences(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	return r.repairDescendantOwnerReferences(ctx, cluster, descendants)
+}
+
+// repairDescendantOwnerReferences removes duplicate owner references from the descendants owned by a Cluster,
+// e.g. left behind by restore or migration flows, and adds the Cluster owner reference back to MachinePools
+// which lost it, so they are deleted together with the Cluster. The Cluster owner reference is also set to block
+// the deletion of the Cluster, so the garbage collector deletes the descendants first.
+// NOTE: The descendants are updated in place.
+func (r *ClusterReconciler) repairDescendantOwnerReferences(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	objs, err := descendants.filterDescendants(cluster, func(metav1.Object) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+
+		owned := util.IsOwnedByObject(accessor, cluster)
+		_, isMachinePool := obj.(*expv1.MachinePool)
+		if !owned && !isMachinePool {
+			continue
+		}
+
+		refs, removed := dedupeOwnerReferences(accessor.GetOwnerReferences())
+		blocked := blockClusterOwnerDeletion(refs, cluster)
+		if owned && removed == 0 && blocked == 0 {
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(obj, r.Client)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			refs = util.EnsureOwnerRef(refs, metav1.OwnerReference{
+				APIVersion:         clusterv1.GroupVersion.String(),
+				Kind:               "Cluster",
+				Name:               cluster.Name,
+				UID:                cluster.UID,
+				BlockOwnerDeletion: pointer.BoolPtr(true),
+			})
+			logger.Info("Adding missing Cluster owner reference to descendant", "kind", fmt.Sprintf("%T", obj), "name", accessor.GetName())
+		}
+		if removed > 0 {
+			logger.Info("Removing duplicate owner references from descendant", "kind", fmt.Sprintf("%T", obj), "name", accessor.GetName(), "removed", removed)
+		}
+		if owned && blocked > 0 {
+			logger.Info("Setting blockOwnerDeletion on the Cluster owner reference of descendant", "kind", fmt.Sprintf("%T", obj), "name", accessor.GetName())
+		}
+		accessor.SetOwnerReferences(refs)
+
+		if err := patchHelper.Patch(ctx, obj); err != nil {
+			return errors.Wrapf(err, "failed to repair owner references of %T %q in namespace %q", obj, accessor.GetName(), cluster.Namespace)
+		}
+	}
+	return nil
+}
+
+// reconcileDescendantLabels propagates the labels of a Cluster to its descendants, if enabled, removing
+// the previously propagated labels that are not present anymore on the Cluster.
+// The keys of the propagated labels are tracked on each descendant using the PropagatedLabelsAnnotation.
+func (r *ClusterReconciler) reconcileDescendantLabels(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	if !r.PropagateLabels {
+		return nil
+	}
+
+	objs, err := descendants.filterDescendants(cluster, func(metav1.Object) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+
+		labels, managed := propagateLabels(cluster.GetLabels(), accessor.GetLabels(), accessor.GetAnnotations()[clusterv1.PropagatedLabelsAnnotation])
+		if reflect.DeepEqual(labels, accessor.GetLabels()) && managed == accessor.GetAnnotations()[clusterv1.PropagatedLabelsAnnotation] {
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(obj, r.Client)
+		if err != nil {
+			return err
+		}
+		accessor.SetLabels(labels)
+		objAnnotations := accessor.GetAnnotations()
+		if managed == "" {
+			delete(objAnnotations, clusterv1.PropagatedLabelsAnnotation)
+		} else {
+			if objAnnotations == nil {
+				objAnnotations = map[string]string{}
+			}
+			objAnnotations[clusterv1.PropagatedLabelsAnnotation] = managed
+		}
+		accessor.SetAnnotations(objAnnotations)
+
+		logger.V(4).Info("Updating labels propagated to descendant", "kind", fmt.Sprintf("%T", obj), "name", accessor.GetName(), "labels", managed)
+		if err := patchHelper.Patch(ctx, obj); err != nil {
+			return errors.Wrapf(err, "failed to propagate labels to %T %q in namespace %q", obj, accessor.GetName(), cluster.Namespace)
+		}
+	}
+	return nil
+}
+
+// reconcileDescendantClusterUIDLabel sets the ClusterUIDLabelName label on the descendants owned by a Cluster,
+// i.e. the descendants with an owner reference to the Cluster with its UID; descendants already labeled are skipped.
+func (r *ClusterReconciler) reconcileDescendantClusterUIDLabel(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	if cluster.UID == "" {
+		return nil
+	}
+
+	objs, err := descendants.filterOwnedDescendants(cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if accessor.GetLabels()[clusterv1.ClusterUIDLabelName] == string(cluster.UID) || !hasOwnerReferenceUID(accessor, cluster.UID) {
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(obj, r.Client)
+		if err != nil {
+			return err
+		}
+		labels := accessor.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[clusterv1.ClusterUIDLabelName] = string(cluster.UID)
+		accessor.SetLabels(labels)
+
+		logger.V(4).Info("Setting Cluster UID label on descendant", "kind", fmt.Sprintf("%T", obj), "name", accessor.GetName())
+		if err := patchHelper.Patch(ctx, obj); err != nil {
+			return errors.Wrapf(err, "failed to set the Cluster UID label on %T %q in namespace %q", obj, accessor.GetName(), cluster.Namespace)
+		}
+	}
+	return nil
+}
+
+// hasOwnerReferenceUID returns true if the object has an owner reference with the given UID.
+func hasOwnerReferenceUID(obj metav1.Object, uid types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileDescendantsLimit reports in the DescendantsWithinLimitCondition a Cluster whose number of descendants
+// exceeds the MaxDescendantsWarnThreshold, if any, emitting a Warning event when the threshold is first exceeded.
+func (r *ClusterReconciler) reconcileDescendantsLimit(_ context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	if r.MaxDescendantsWarnThreshold <= 0 {
+		return nil
+	}
+
+	if count := descendants.length(); count > r.MaxDescendantsWarnThreshold {
+		if !conditions.IsFalse(cluster, clusterv1.DescendantsWithinLimitCondition) {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "TooManyDescendants",
+				"Cluster has %d descendants, exceeding the threshold of %d", count, r.MaxDescendantsWarnThreshold)
+		}
+		conditions.MarkFalse(cluster, clusterv1.DescendantsWithinLimitCondition, clusterv1.TooManyDescendantsReason,
+			clusterv1.ConditionSeverityInfo, "%d descendants exceed the threshold of %d", count, r.MaxDescendantsWarnThreshold)
+		return nil
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.DescendantsWithinLimitCondition)
+	return nil
+}
+
+// reconcileDescendantTopology exports a summary of the descendants of a Cluster in the DescendantTopologyAnnotation,
+// if ExportDescendantTopology is set, removing the annotation otherwise.
+func (r *ClusterReconciler) reconcileDescendantTopology(_ context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	if !r.ExportDescendantTopology {
+		delete(cluster.Annotations, clusterv1.DescendantTopologyAnnotation)
+		return nil
+	}
+
+	topology, err := marshalDescendantTopology(descendants.topology(), maxDescendantTopologySize)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the descendant topology of Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[clusterv1.DescendantTopologyAnnotation] = topology
+	return nil
+}
+
+// reconcileNodeDrainTimeout propagates the NodeDrainTimeout of a Cluster, if any, to the Machines of the Cluster which
+// do not define their own NodeDrainTimeout, marking them with the NodeDrainTimeoutInheritedAnnotation; the timeout of
+// the marked Machines is updated when the one of the Cluster changes, and removed once the Cluster does not define it.
+func (r *ClusterReconciler) reconcileNodeDrainTimeout(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	timeout := cluster.Spec.NodeDrainTimeout
+	return descendants.eachDescendant(func(metav1.Object) bool { return true }, func(o runtime.Object) error {
+		m, ok := o.(*clusterv1.Machine)
+		if !ok {
+			return nil
+		}
+		if _, inherited := m.Annotations[clusterv1.NodeDrainTimeoutInheritedAnnotation]; inherited {
+			if reflect.DeepEqual(m.Spec.NodeDrainTimeout, timeout) {
+				return nil
+			}
+		} else if m.Spec.NodeDrainTimeout != nil || timeout == nil {
+			// Machines defining their own node drain timeout are left untouched.
+			return nil
+		}
+
+		patchHelper, err := patch.NewHelper(m, r.Client)
+		if err != nil {
+			return err
+		}
+		if timeout == nil {
+			m.Spec.NodeDrainTimeout = nil
+			delete(m.Annotations, clusterv1.NodeDrainTimeoutInheritedAnnotation)
+		} else {
+			m.Spec.NodeDrainTimeout = timeout.DeepCopy()
+			if m.Annotations == nil {
+				m.Annotations = map[string]string{}
+			}
+			m.Annotations[clusterv1.NodeDrainTimeoutInheritedAnnotation] = ""
+		}
+
+		logger.V(4).Info("Propagating node drain timeout to Machine", "name", m.Name, "timeout", m.Spec.NodeDrainTimeout)
+		if err := patchHelper.Patch(ctx, m); err != nil {
+			return errors.Wrapf(err, "failed to propagate node drain timeout to Machine %q in namespace %q", m.Name, m.Namespace)
+		}
+		return nil
+	})
+}
+
+// reconcileMachineHealthChecksDisabled sets the SkipRemediationAnnotation on the worker Machines of a Cluster whose
+// MachineHealthChecks are disabled, and removes the annotations previously set by the Cluster once re-enabled.
+// The worker Machines are the ones MachineHealthChecks remediate, i.e. the Machines owned by MachineSets;
+// control plane Machines and MachinePool Machines are never remediated.
+// The Cluster itself carries the SkipRemediationAnnotation while its Machines may have been annotated, so
+// the Machines are not walked at all for Clusters which never disabled their MachineHealthChecks.
+func (r *ClusterReconciler) reconcileMachineHealthChecksDisabled(ctx context.Context, cluster *clusterv1.Cluster, descendants *clusterDescendants) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	disabled := cluster.Spec.DisableMachineHealthChecks != nil && *cluster.Spec.DisableMachineHealthChecks
+	if _, ok := cluster.Annotations[clusterv1.SkipRemediationAnnotation]; !disabled && !ok {
+		return nil
+	}
+
+	if err := descendants.eachDescendant(func(metav1.Object) bool { return true }, func(o runtime.Object) error {
+		m, ok := o.(*clusterv1.Machine)
+		if !ok {
+			return nil
+		}
+		value, ok := m.Annotations[clusterv1.SkipRemediationAnnotation]
+		if disabled == ok || (ok && value != cluster.Name) {
+			return nil
+		}
+
+		patchHelper, err := patch.NewHelper(m, r.Client)
+		if err != nil {
+			return err
+		}
+		if disabled {
+			if m.Annotations == nil {
+				m.Annotations = map[string]string{}
+			}
+			m.Annotations[clusterv1.SkipRemediationAnnotation] = cluster.Name
+		} else {
+			delete(m.Annotations, clusterv1.SkipRemediationAnnotation)
+		}
+
+		logger.V(4).Info("Toggling remediation of Machine", "name", m.Name, "disabled", disabled)
+		if err := patchHelper.Patch(ctx, m); err != nil {
+			return errors.Wrapf(err, "failed to toggle remediation of Machine %q in namespace %q", m.Name, m.Namespace)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// The annotation is removed from the Cluster only once all of its Machines have been re-enabled.
+	if !disabled {
+		delete(cluster.Annotations, clusterv1.SkipRemediationAnnotation)
+		return nil
+	}
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[clusterv1.SkipRemediationAnnotation] = cluster.Name
+	return nil
+}
+
+// propagateLabels returns the labels of a descendant after propagating the labels of its Cluster,
+// along with the comma separated, sorted keys of the labels managed by the propagation.
+// Labels previously managed, as defined by the current managed keys, are removed if not present anymore on the Cluster.
+func propagateLabels(clusterLabels, labels map[string]string, managedKeys string) (map[string]string, string) {
+	previouslyManaged := sets.NewString()
+	if managedKeys != "" {
+		previouslyManaged.Insert(strings.Split(managedKeys, ",")...)
+	}
+
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	// Remove the labels not present anymore on the Cluster.
+	for _, k := range previouslyManaged.List() {
+		if _, ok := clusterLabels[k]; !ok {
+			delete(result, k)
+			previouslyManaged.Delete(k)
+		}
+	}
+
+	managed := sets.NewString()
+	for k, v := range clusterLabels {
+		if !isPropagatableLabel(k) {
+			continue
+		}
+		// Do not take over labels defined on the descendant itself.
+		if _, ok := result[k]; ok && !previouslyManaged.Has(k) {
+			continue
+		}
+		result[k] = v
+		managed.Insert(k)
+	}
+
+	return result, strings.Join(managed.List(), ",")
+}
+
+// isPropagatableLabel returns true if the label can be propagated from a Cluster to its descendants,
+// i.e. it is not in the cluster.x-k8s.io domain.
+func isPropagatableLabel(key string) bool {
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		return prefix != clusterv1.GroupVersion.Group && !strings.HasSuffix(prefix, "."+clusterv1.GroupVersion.Group)
+	}
+	return true
+}
+
+// dedupeOwnerReferences returns the given owner references without duplicates, keeping the first occurrence,
+// along with the number of references that have been removed.
+func dedupeOwnerReferences(refs []metav1.OwnerReference) ([]metav1.OwnerReference, int) {
+	seen := make(map[metav1.OwnerReference]bool, len(refs))
+	deduped := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		key := metav1.OwnerReference{APIVersion: ref.APIVersion, Kind: ref.Kind, Name: ref.Name, UID: ref.UID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, ref)
+	}
+	return deduped, len(refs) - len(deduped)
+}
+
+// blockClusterOwnerDeletion sets BlockOwnerDeletion on the owner references pointing to the given Cluster, if not set yet,
+// returning how many owner references have been changed.
+// NOTE: The owner references are updated in place.
+func blockClusterOwnerDeletion(refs []metav1.OwnerReference, cluster *clusterv1.Cluster) int {
+	changed := 0
+	for i := range refs {
+		ref := &refs[i]
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != clusterv1.GroupVersion.Group || ref.Kind != "Cluster" || ref.Name != cluster.Name {
+			continue
+		}
+		if ref.BlockOwnerDeletion == nil || !*ref.BlockOwnerDeletion {
+			ref.BlockOwnerDeletion = pointer.BoolPtr(true)
+			changed++
+		}
+	}
+	return changed
+}
+
+// reconcileControlPlaneReachable probes the API server of the workload cluster, if enabled,
+// and reports the result in the ControlPlaneReachableCondition.
+func (r *ClusterReconciler) reconcileControlPlaneReachable(ctx context.Context, cluster *clusterv1.Cluster) error {
+	logger := r.Log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace)
+
+	if !r.EnableControlPlaneProbe || cluster.Spec.ControlPlaneEndpoint.IsZero() {
+		return nil
+	}
+
+	kubeconfigData, err := kubeconfig.FromSecret(ctx, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			// There is nothing to probe until the kubeconfig secret gets generated.
+			logger.V(4).Info("Skipping control plane probe, kubeconfig secret does not exist yet")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve kubeconfig secret for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+
+	timeout := r.ControlPlaneProbeTimeout
+	if timeout == 0 {
+		timeout = defaultControlPlaneProbeTimeout
+	}
+	kubeClient, err := r.controlPlaneProbeClients.get(cluster.UID, kubeconfigData, timeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create client for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+	}
+
+	// Use the version endpoint as a lightweight way to check the API server is answering.
+	if _, err := kubeClient.Discovery().ServerVersion(); err != nil {
+		logger.V(3).Info("Control plane endpoint is not reachable", "endpoint", cluster.Spec.ControlPlaneEndpoint.String(), "error", err.Error())
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneReachableCondition, clusterv1.ControlPlaneUnreachableReason,
+			clusterv1.ConditionSeverityWarning, "Failed to reach the API server: %v", err)
+		return nil
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneReachableCondition)
+	return nil
+}
+
+// controlPlaneProbeClients caches, by Cluster, the clients used to probe the workload cluster API servers, so
+// connections are reused across reconciliations instead of building a new client each time.
+type controlPlaneProbeClients struct {
+	lock    sync.Mutex
+	clients map[types.UID]controlPlaneProbeClient
+}
+
+// controlPlaneProbeClient is a client used to probe a workload cluster API server, along with the kubeconfig
+// and the timeout it has been built with.
+type controlPlaneProbeClient struct {
+	kubeconfig []byte
+	timeout    time.Duration
+	client     kubernetes.Interface
+}
+
+// get returns the client cached for a Cluster, building a new one if the cached client has been built
+// with a different kubeconfig or timeout, e.g. because the kubeconfig secret has been regenerated.
+func (p *controlPlaneProbeClients) get(cluster types.UID, kubeconfigData []byte, timeout time.Duration) (kubernetes.Interface, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if cached, ok := p.clients[cluster]; ok && cached.timeout == timeout && bytes.Equal(cached.kubeconfig, kubeconfigData) {
+		return cached.client, nil
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigData)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Timeout = timeout
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.clients == nil {
+		p.clients = map[types.UID]controlPlaneProbeClient{}
+	}
+	p.clients[cluster] = controlPlaneProbeClient{kubeconfig: kubeconfigData, timeout: timeout, client: kubeClient}
+	return kubeClient, nil
+}
+
+// forget drops the client cached for a Cluster, e.g. once the Cluster has been deleted.
+func (p *controlPlaneProbeClients) forget(cluster types.UID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.clients, cluster)
+}
@@ -18,19 +18,39 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -135,9 +155,10 @@ func TestClusterReconcilePhases(t *testing.T) {
 					c = fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), tt.cluster)
 				}
 				r := &ClusterReconciler{
-					Client: c,
-					Log:    log.Log,
-					scheme: scheme.Scheme,
+					Client:   c,
+					Log:      log.Log,
+					scheme:   scheme.Scheme,
+					recorder: record.NewFakeRecorder(32),
 				}
 
 				err := r.reconcileInfrastructure(context.Background(), tt.cluster)
@@ -172,8 +193,30 @@ func TestClusterReconcilePhases(t *testing.T) {
 			wantRequeue bool
 		}{
 			{
-				name:    "cluster not provisioned, apiEndpoint is not set",
-				cluster: &clusterv1.Cluster{},
+				name:        "cluster not provisioned, apiEndpoint is not set, should return RequeueAfterError",
+				cluster:     &clusterv1.Cluster{},
+				wantErr:     true,
+				wantRequeue: true,
+			},
+			{
+				name: "cluster not provisioned, apiEndpoint host is not set, should return RequeueAfterError",
+				cluster: &clusterv1.Cluster{
+					Spec: clusterv1.ClusterSpec{
+						ControlPlaneEndpoint: clusterv1.APIEndpoint{
+							Port: 8443,
+						},
+					},
+				},
+				wantErr:     true,
+				wantRequeue: true,
+			},
+			{
+				name: "cluster with a control plane provider, apiEndpoint is not set",
+				cluster: &clusterv1.Cluster{
+					Spec: clusterv1.ClusterSpec{
+						ControlPlaneRef: &corev1.ObjectReference{},
+					},
+				},
 				wantErr: false,
 			},
 			{
@@ -214,6 +257,7 @@ func TestClusterReconcilePhases(t *testing.T) {
 				}
 				r := &ClusterReconciler{
 					Client: c,
+					Log:    log.Log,
 					scheme: scheme.Scheme,
 				}
 				err := r.reconcileKubeconfig(context.Background(), tt.cluster)
@@ -229,6 +273,98 @@ func TestClusterReconcilePhases(t *testing.T) {
 	})
 }
 
+func TestClusterReconciler_reconcileKubeconfigConfigMap(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	// The Kubeconfig Secret is managed by the control plane provider, but the ConfigMap is exported anyway.
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+			UID:       "uid",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443},
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+				Kind:       "GenericControlPlane",
+				Name:       "test-control-plane",
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+	r := &ClusterReconciler{
+		Client:                    c,
+		Log:                       log.Log,
+		ExportKubeconfigConfigMap: true,
+	}
+
+	g.Expect(r.reconcileKubeconfig(ctx, cluster)).To(Succeed())
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: kubeconfigConfigMapName(cluster.Name)}, configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(Equal(map[string]string{
+		"server":      "https://1.2.3.4:6443",
+		"clusterName": "test-cluster",
+	}))
+	g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+	g.Expect(configMap.OwnerReferences[0].UID).To(Equal(cluster.UID))
+
+	// The ConfigMap follows the control plane endpoint.
+	cluster.Spec.ControlPlaneEndpoint.Port = 443
+	g.Expect(r.reconcileKubeconfig(ctx, cluster)).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: kubeconfigConfigMapName(cluster.Name)}, configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(HaveKeyWithValue("server", "https://1.2.3.4:443"))
+}
+
+func TestClusterReconciler_reconcileKubeconfigRepair(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+			UID:       "uid",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443},
+		},
+	}
+
+	certificates := secret.Certificates{&secret.Certificate{Purpose: secret.ClusterCA}}
+	g.Expect(certificates.Generate()).To(Succeed())
+	caSecret := certificates.GetByPurpose(secret.ClusterCA).AsSecret(util.ObjectKey(cluster), metav1.OwnerReference{})
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, caSecret)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	g.Expect(r.reconcileKubeconfig(ctx, cluster)).To(Succeed())
+
+	key := client.ObjectKey{Namespace: cluster.Namespace, Name: secret.Name(cluster.Name, secret.Kubeconfig)}
+	configSecret := &corev1.Secret{}
+	g.Expect(c.Get(ctx, key, configSecret)).To(Succeed())
+	original := configSecret.Data[secret.KubeconfigDataName]
+
+	// An untouched Kubeconfig is not regenerated.
+	g.Expect(r.reconcileKubeconfig(ctx, cluster)).To(Succeed())
+	g.Expect(c.Get(ctx, key, configSecret)).To(Succeed())
+	g.Expect(configSecret.Data[secret.KubeconfigDataName]).To(Equal(original))
+
+	// An edited Kubeconfig is regenerated.
+	configSecret.Data[secret.KubeconfigDataName] = []byte("tampered")
+	g.Expect(c.Update(ctx, configSecret)).To(Succeed())
+
+	g.Expect(r.reconcileKubeconfig(ctx, cluster)).To(Succeed())
+	g.Expect(c.Get(ctx, key, configSecret)).To(Succeed())
+	g.Expect(kubeconfig.VerifySecret(ctx, c, util.ObjectKey(cluster), "1.2.3.4:6443", configSecret)).To(Succeed())
+}
+
 func TestClusterReconciler_reconcilePhase(t *testing.T) {
 	cluster := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -240,14 +376,30 @@ func TestClusterReconciler_reconcilePhase(t *testing.T) {
 	createClusterError := capierrors.CreateClusterError
 	failureMsg := "Create failed"
 
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-machine",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: "test-cluster",
+			},
+		},
+	}
+
 	tests := []struct {
 		name      string
 		cluster   *clusterv1.Cluster
+		machine   *clusterv1.Machine
 		wantPhase clusterv1.ClusterPhase
 	}{
 		{
-			name:      "cluster not provisioned",
+			name:      "cluster without references nor machines",
 			cluster:   cluster,
+			wantPhase: clusterv1.ClusterPhaseUnknown,
+		},
+		{
+			name:      "cluster not provisioned",
+			cluster:   cluster.DeepCopy(),
+			machine:   machine,
 			wantPhase: clusterv1.ClusterPhasePending,
 		},
 		{
@@ -359,13 +511,2749 @@ func TestClusterReconciler_reconcilePhase(t *testing.T) {
 			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
 
 			c := fake.NewFakeClientWithScheme(scheme.Scheme, tt.cluster)
+			if tt.machine != nil {
+				g.Expect(c.Create(context.TODO(), tt.machine.DeepCopy())).To(Succeed())
+			}
 
 			r := &ClusterReconciler{
 				Client: c,
+				Log:    log.Log,
 				scheme: scheme.Scheme,
 			}
 			r.reconcilePhase(context.TODO(), tt.cluster)
 			g.Expect(tt.cluster.Status.GetTypedPhase()).To(Equal(tt.wantPhase))
+			if tt.wantPhase == clusterv1.ClusterPhaseUnknown {
+				g.Expect(conditions.IsFalse(tt.cluster, clusterv1.ReferencesDefinedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(tt.cluster, clusterv1.ReferencesDefinedCondition)).To(Equal(clusterv1.MissingReferencesReason))
+			} else {
+				g.Expect(conditions.Has(tt.cluster, clusterv1.ReferencesDefinedCondition)).To(BeFalse())
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_reconcilePhaseFromConditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		ready     *clusterv1.Condition
+		wantPhase clusterv1.ClusterPhase
+	}{
+		{
+			name:      "without a Ready condition the phase is Pending",
+			wantPhase: clusterv1.ClusterPhasePending,
+		},
+		{
+			name:      "with a true Ready condition the phase is Provisioned",
+			ready:     conditions.TrueCondition(clusterv1.ReadyCondition),
+			wantPhase: clusterv1.ClusterPhaseProvisioned,
+		},
+		{
+			name:      "with a deleting reason the phase is Deleting",
+			ready:     conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.ControlPlaneDeletingReason, clusterv1.ConditionSeverityInfo, ""),
+			wantPhase: clusterv1.ClusterPhaseDeleting,
+		},
+		{
+			name:      "with a deleted reason the phase is Deleting",
+			ready:     conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.InfrastructureDeletedReason, clusterv1.ConditionSeverityWarning, ""),
+			wantPhase: clusterv1.ClusterPhaseDeleting,
+		},
+		{
+			name:      "with missing references the phase is Unknown",
+			ready:     conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.MissingReferencesReason, clusterv1.ConditionSeverityWarning, ""),
+			wantPhase: clusterv1.ClusterPhaseUnknown,
+		},
+		{
+			name:      "with severity Error the phase is Failed",
+			ready:     conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.TimedOutReason, clusterv1.ConditionSeverityError, ""),
+			wantPhase: clusterv1.ClusterPhaseFailed,
+		},
+		{
+			name:      "with any other reason the phase is Provisioning",
+			ready:     conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+			wantPhase: clusterv1.ClusterPhaseProvisioning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{}
+			if tt.ready != nil {
+				conditions.Set(cluster, tt.ready)
+			}
+			g.Expect(phaseFromReadyCondition(cluster)).To(Equal(tt.wantPhase))
+		})
+	}
+
+	t.Run("the Ready condition is summarized before deriving the phase", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{},
+			},
+			Status: clusterv1.ClusterStatus{
+				// The phase is not inferred from the other signals.
+				InfrastructureReady: true,
+			},
+		}
+		conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletingReason, clusterv1.ConditionSeverityWarning, "")
+
+		r := &ClusterReconciler{
+			Client:              fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+			Log:                 log.Log,
+			PhaseFromConditions: true,
+		}
+		r.reconcilePhase(ctx, cluster)
+		g.Expect(cluster.Status.GetTypedPhase()).To(Equal(clusterv1.ClusterPhaseDeleting))
+	})
+}
+
+func TestClusterReconciler_reconcileReferencedKinds(t *testing.T) {
+	newInfra := func(kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       kind,
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "test",
+				},
+				"spec": map[string]interface{}{},
+			},
+		}
+	}
+	instance := newInfra("InfrastructureMachine", "instance")
+	template := newInfra("InfrastructureMachineTemplate", "template")
+
+	tests := []struct {
+		name          string
+		infraConfig   *unstructured.Unstructured
+		wantCondition bool
+	}{
+		{
+			name:        "reference to an instance",
+			infraConfig: instance,
+		},
+		{
+			name:          "reference to a template",
+			infraConfig:   template,
+			wantCondition: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: tt.infraConfig.GetAPIVersion(),
+						Kind:       tt.infraConfig.GetKind(),
+						Name:       tt.infraConfig.GetName(),
+					},
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme,
+					external.TestGenericInfrastructureCRD.DeepCopy(), external.TestGenericInfrastructureTemplateCRD.DeepCopy(),
+					cluster, instance.DeepCopy(), template.DeepCopy()),
+				Log:                     log.Log,
+				recorder:                record.NewFakeRecorder(32),
+				ValidateReferencedKinds: true,
+			}
+
+			_, _ = r.reconcile(ctx, cluster)
+			if !tt.wantCondition {
+				g.Expect(conditions.Has(cluster, clusterv1.ReferencedKindsValidCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.IsFalse(cluster, clusterv1.ReferencedKindsValidCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(cluster, clusterv1.ReferencedKindsValidCondition)).To(Equal(clusterv1.TemplateReferencedReason))
+			g.Expect(conditions.GetMessage(cluster, clusterv1.ReferencedKindsValidCondition)).To(ContainSubstring("Spec.InfrastructureRef"))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileControlPlaneReachable(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	// Fake API server endpoint which answers to the probe only when reachable is set.
+	var reachable int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&reachable) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"major": "1", "minor": "18", "gitVersion": "v1.18.2"}`))
+	}))
+	defer server.Close()
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{
+				Host: "1.2.3.4",
+				Port: 8443,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, cluster)
+	r := &ClusterReconciler{
+		Client:                   c,
+		Log:                      log.Log,
+		EnableControlPlaneProbe:  true,
+		ControlPlaneProbeTimeout: time.Second,
+	}
+
+	// Without a kubeconfig secret the probe is skipped.
+	g.Expect(r.reconcileControlPlaneReachable(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.Has(cluster, clusterv1.ControlPlaneReachableCondition)).To(BeFalse())
+
+	kubeconfigData := kubeconfig.FromEnvTestConfig(&rest.Config{Host: server.URL, Username: "test"}, cluster)
+	g.Expect(c.Create(ctx, kubeconfig.GenerateSecret(cluster, kubeconfigData))).To(Succeed())
+
+	g.Expect(r.reconcileControlPlaneReachable(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneReachableCondition)).To(BeTrue())
+	probeClient := r.controlPlaneProbeClients.clients[cluster.UID].client
+
+	atomic.StoreInt32(&reachable, 0)
+	g.Expect(r.reconcileControlPlaneReachable(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneReachableCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneReachableCondition)).To(Equal(clusterv1.ControlPlaneUnreachableReason))
+
+	atomic.StoreInt32(&reachable, 1)
+	g.Expect(r.reconcileControlPlaneReachable(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneReachableCondition)).To(BeTrue())
+
+	// The client built for the first probe is reused by the following ones.
+	g.Expect(r.controlPlaneProbeClients.clients).To(HaveLen(1))
+	g.Expect(r.controlPlaneProbeClients.clients[cluster.UID].client).To(BeIdenticalTo(probeClient))
+}
+
+func TestClusterReconciler_reconcileInfrastructurePaused(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+				"annotations": map[string]interface{}{
+					clusterv1.PausedAnnotation: "true",
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": false,
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               string(clusterv1.ReadyCondition),
+						"status":             string(corev1.ConditionFalse),
+						"severity":           string(clusterv1.ConditionSeverityWarning),
+						"reason":             "LoadBalancerFailed",
+						"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig.DeepCopy())
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+
+	// The paused infrastructure object should not be modified.
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+	obj.SetKind("InfrastructureMachine")
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "test-namespace", Name: "test"}, obj)).To(Succeed())
+	g.Expect(obj.GetOwnerReferences()).To(BeEmpty())
+	g.Expect(obj.GetLabels()).NotTo(HaveKey(clusterv1.ClusterLabelName))
+
+	// The state of the paused infrastructure object should still surface on the Cluster.
+	g.Expect(conditions.Has(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal("LoadBalancerFailed"))
+	g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+}
+
+func TestClusterReconciler_reconcileInfrastructureDeleting(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":              "test",
+				"namespace":         "test-namespace",
+				"deletionTimestamp": metav1.Now().UTC().Format(time.RFC3339),
+			},
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+		Log:      log.Log,
+		scheme:   scheme.Scheme,
+		recorder: recorder,
+	}
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+
+	// The endpoint of an infrastructure object being deleted should not be used.
+	g.Expect(cluster.Spec.ControlPlaneEndpoint.IsZero()).To(BeTrue())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+
+	g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureDeletingReason))
+	g.Expect(conditions.Get(cluster, clusterv1.InfrastructureReadyCondition).Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+	g.Expect(recorder.Events).To(HaveLen(1))
+}
+
+func TestClusterReconciler_reconcileInfrastructureOwnerReference(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	tests := []struct {
+		name           string
+		annotations    map[string]interface{}
+		ownerRefs      []interface{}
+		wantOwnerRefed bool
+	}{
+		{
+			name:           "fresh infrastructure object, should add the Cluster owner reference",
+			wantOwnerRefed: true,
+		},
+		{
+			name: "infrastructure object controlled by another controller, should add the Cluster owner reference",
+			ownerRefs: []interface{}{
+				map[string]interface{}{
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"kind":       "InfrastructureProvider",
+					"name":       "provider",
+					"uid":        "provider-uid",
+					"controller": true,
+				},
+			},
+			wantOwnerRefed: true,
+		},
+		{
+			name: "externally managed infrastructure object, should not add the Cluster owner reference",
+			annotations: map[string]interface{}{
+				clusterv1.ManagedByAnnotation: "external-system",
+			},
+			wantOwnerRefed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			metadata := map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			}
+			if tt.annotations != nil {
+				metadata["annotations"] = tt.annotations
+			}
+			if tt.ownerRefs != nil {
+				metadata["ownerReferences"] = tt.ownerRefs
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata":   metadata,
+				},
+			}
+
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig)
+			r := &ClusterReconciler{
+				Client: c,
+				Log:    log.Log,
+				scheme: scheme.Scheme,
+			}
+
+			g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+
+			got := &unstructured.Unstructured{}
+			got.SetGroupVersionKind(infraConfig.GroupVersionKind())
+			g.Expect(c.Get(ctx, util.ObjectKey(infraConfig), got)).To(Succeed())
+			g.Expect(util.IsOwnedByObject(got, cluster)).To(Equal(tt.wantOwnerRefed))
+
+			// The Cluster owns the infrastructure object without controlling it, blocking its deletion.
+			for _, ref := range got.GetOwnerReferences() {
+				if ref.Kind != "Cluster" {
+					continue
+				}
+				g.Expect(ref.Controller).To(BeNil())
+				g.Expect(ref.BlockOwnerDeletion).To(Equal(pointer.BoolPtr(true)))
+			}
+			wantOwnerRefs := len(tt.ownerRefs)
+			if tt.wantOwnerRefed {
+				wantOwnerRefs++
+			}
+			g.Expect(got.GetOwnerReferences()).To(HaveLen(wantOwnerRefs))
+
+			// Externally managed infrastructure objects are surfaced in the InfrastructureManagedByCondition.
+			if tt.wantOwnerRefed {
+				g.Expect(conditions.Has(cluster, clusterv1.InfrastructureManagedByCondition)).To(BeFalse())
+			} else {
+				g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureManagedByCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureManagedByCondition)).To(Equal(clusterv1.ExternallyManagedReason))
+				g.Expect(conditions.GetMessage(cluster, clusterv1.InfrastructureManagedByCondition)).To(ContainSubstring("external-system"))
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureFailureDomains(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newCluster := func() *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+					Kind:       "InfrastructureMachine",
+					Name:       "test",
+				},
+			},
+		}
+	}
+	newInfraConfig := func(status map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "InfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+				"metadata": map[string]interface{}{
+					"name":      "test",
+					"namespace": "test-namespace",
+				},
+				"spec": map[string]interface{}{
+					"controlPlaneEndpoint": map[string]interface{}{
+						"host": "1.2.3.4",
+						"port": int64(6443),
+					},
+				},
+				"status": status,
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		infraConfig        *unstructured.Unstructured
+		wantFailureDomains clusterv1.FailureDomains
+	}{
+		{
+			name: "infrastructure exposes failure domains",
+			infraConfig: newInfraConfig(map[string]interface{}{
+				"ready": true,
+				"failureDomains": map[string]interface{}{
+					"domain-a": map[string]interface{}{
+						"controlPlane": true,
+					},
+					"domain-b": map[string]interface{}{
+						"controlPlane": false,
+						"attributes": map[string]interface{}{
+							"zone": "b",
+						},
+					},
+				},
+			}),
+			wantFailureDomains: clusterv1.FailureDomains{
+				"domain-a": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"domain-b": clusterv1.FailureDomainSpec{ControlPlane: false, Attributes: map[string]string{"zone": "b"}},
+			},
+		},
+		{
+			name: "infrastructure does not expose failure domains",
+			infraConfig: newInfraConfig(map[string]interface{}{
+				"ready": true,
+			}),
+			wantFailureDomains: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newCluster()
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, tt.infraConfig),
+				Log:    log.Log,
+				scheme: scheme.Scheme,
+			}
+
+			g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+			g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+			g.Expect(cluster.Status.FailureDomains).To(Equal(tt.wantFailureDomains))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureEndpointDrift(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			},
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig)
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		scheme:   scheme.Scheme,
+		recorder: recorder,
+	}
+
+	// The endpoint is copied from the infrastructure object.
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443}))
+
+	// The endpoint is in sync with the infrastructure object.
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneEndpointInSyncCondition)).To(BeTrue())
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// Change the endpoint on the infrastructure object.
+	g.Expect(c.Get(ctx, util.ObjectKey(infraConfig), infraConfig)).To(Succeed())
+	g.Expect(unstructured.SetNestedField(infraConfig.Object, "5.6.7.8", "spec", "controlPlaneEndpoint", "host")).To(Succeed())
+	g.Expect(c.Update(ctx, infraConfig)).To(Succeed())
+
+	// The drift is reported, but the Cluster endpoint is not overwritten.
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443}))
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneEndpointInSyncCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneEndpointInSyncCondition)).To(Equal(clusterv1.ControlPlaneEndpointDriftedReason))
+	g.Expect(conditions.Get(cluster, clusterv1.ControlPlaneEndpointInSyncCondition).Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+	g.Expect(recorder.Events).To(HaveLen(1))
+}
+
+func TestClusterReconciler_reconcileInfrastructureNotFound(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client:                             fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster),
+		Log:                                log.Log,
+		scheme:                             scheme.Scheme,
+		InfrastructureNotFoundRequeueAfter: 3 * time.Second,
+	}
+
+	err := r.reconcileInfrastructure(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	requeueErr, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(requeueErr.GetRequeueAfter()).To(Equal(3 * time.Second))
+
+	g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.WaitingForInfrastructureFallbackReason))
+	g.Expect(conditions.Get(cluster, clusterv1.InfrastructureReadyCondition).Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+}
+
+func TestClusterReconciler_reconcileInfrastructureDeleted(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			},
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig)
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		scheme:   scheme.Scheme,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+
+	// The infrastructure object is deleted out of band.
+	g.Expect(c.Delete(ctx, infraConfig)).To(Succeed())
+
+	// The Cluster is not provisioned anymore, and keeps reporting the infrastructure object as deleted.
+	for i := 0; i < 2; i++ {
+		err := r.reconcileInfrastructure(ctx, cluster)
+		g.Expect(err).To(HaveOccurred())
+		_, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+		g.Expect(ok).To(BeTrue())
+
+		g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+		g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureDeletedReason))
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureRecreated(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			Annotations: map[string]string{
+				clusterv1.InfrastructureTemplateAnnotation: "test-template",
+			},
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+		Status: clusterv1.ClusterStatus{
+			InfrastructureReady: true,
+		},
+	}
+	infraTemplate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachineTemplate",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test-template",
+				"namespace": "test-namespace",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"size": "3xlarge",
+					},
+				},
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(),
+		external.TestGenericInfrastructureTemplateCRD.DeepCopy(), cluster, infraTemplate)
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client:                        c,
+		Log:                           log.Log,
+		RecreateDeletedInfrastructure: true,
+		scheme:                        scheme.Scheme,
+		recorder:                      recorder,
+	}
+
+	// The infrastructure object is reported as deleted, and recreated from the template.
+	err := r.reconcileInfrastructure(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	_, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureDeletedReason))
+
+	infraConfig, err := external.Get(ctx, c, cluster.Spec.InfrastructureRef, cluster.Namespace)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(infraConfig.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterLabelName, cluster.Name))
+	g.Expect(infraConfig.GetOwnerReferences()).To(HaveLen(1))
+	size, _, err := unstructured.NestedString(infraConfig.Object, "spec", "size")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(size).To(Equal("3xlarge"))
+
+	g.Expect(recorder.Events).To(HaveLen(2))
+	g.Expect(<-recorder.Events).To(ContainSubstring("InfrastructureDeleted"))
+	g.Expect(<-recorder.Events).To(ContainSubstring("InfrastructureRecreated"))
+}
+
+func TestClusterReconciler_reconcileInfrastructureRecreateFailed(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			Annotations: map[string]string{
+				clusterv1.InfrastructureTemplateAnnotation: "test-template",
+			},
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+		Status: clusterv1.ClusterStatus{
+			InfrastructureReady: true,
+		},
+	}
+	infraTemplate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachineTemplate",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test-template",
+				"namespace": "test-namespace",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{},
+				},
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(),
+		external.TestGenericInfrastructureTemplateCRD.DeepCopy(), cluster)
+	r := &ClusterReconciler{
+		Client:                        c,
+		Log:                           log.Log,
+		RecreateDeletedInfrastructure: true,
+		scheme:                        scheme.Scheme,
+		recorder:                      record.NewFakeRecorder(32),
+	}
+
+	// The template does not exist, so the infrastructure object can't be recreated.
+	err := r.reconcileInfrastructure(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	_, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureRecreateFailedReason))
+
+	// The next attempt is not made before the backoff elapses.
+	g.Expect(c.Create(ctx, infraTemplate)).To(Succeed())
+	err = r.reconcileInfrastructure(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	_, ok = errors.Cause(err).(capierrors.HasRequeueAfterError)
+	g.Expect(ok).To(BeTrue())
+	_, err = external.Get(ctx, c, cluster.Spec.InfrastructureRef, cluster.Namespace)
+	g.Expect(apierrors.IsNotFound(errors.Cause(err))).To(BeTrue())
+
+	// Once the backoff elapsed, the infrastructure object is recreated.
+	for i := range cluster.Status.Conditions {
+		if cluster.Status.Conditions[i].Type == clusterv1.InfrastructureReadyCondition {
+			cluster.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-r.infrastructureNotFoundRequeueAfter()))
+		}
+	}
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).NotTo(Succeed())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureDeletedReason))
+	_, err = external.Get(ctx, c, cluster.Spec.InfrastructureRef, cluster.Namespace)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// An infrastructure object already recreated by someone else is not reported as a failure.
+	g.Expect(r.recreateInfrastructure(ctx, cluster)).To(Succeed())
+}
+
+func TestClusterReconciler_reconcileInfrastructureNotFoundThreshold(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			UID:       "uid",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			},
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig.DeepCopy())
+	r := &ClusterReconciler{
+		Client:                          c,
+		Log:                             log.Log,
+		scheme:                          scheme.Scheme,
+		recorder:                        record.NewFakeRecorder(32),
+		InfrastructureNotFoundThreshold: 2,
+	}
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+
+	// The infrastructure object is briefly not found while being recreated: the Cluster keeps reporting
+	// the infrastructure as ready, and checks again later.
+	g.Expect(c.Delete(ctx, infraConfig)).To(Succeed())
+
+	err := r.reconcileInfrastructure(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	_, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+
+	g.Expect(c.Create(ctx, infraConfig.DeepCopy())).To(Succeed())
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+
+	// Finding the infrastructure object again resets the count, so the infrastructure is reported as deleted
+	// only once the object has not been found for the configured number of consecutive reconciliations.
+	g.Expect(c.Delete(ctx, infraConfig)).To(Succeed())
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).NotTo(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).NotTo(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureDeletedReason))
+}
+
+func TestClusterReconciler_reconcileInfrastructureRetryAfter(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	tests := []struct {
+		name        string
+		status      map[string]interface{}
+		wantRequeue time.Duration
+	}{
+		{
+			name:   "infrastructure not ready without a retry hint, should not requeue",
+			status: map[string]interface{}{"ready": false},
+		},
+		{
+			name:        "infrastructure not ready with a retry hint, should requeue after the hint",
+			status:      map[string]interface{}{"ready": false, "retryAfterSeconds": int64(30)},
+			wantRequeue: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+					"status": tt.status,
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+				Log:    log.Log,
+				scheme: scheme.Scheme,
+			}
+
+			err := r.reconcileInfrastructure(ctx, cluster)
+			if tt.wantRequeue == 0 {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			requeueErr, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(requeueErr.GetRequeueAfter()).To(Equal(tt.wantRequeue))
+			g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileControlPlaneWithoutProvider(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+	newMachine := func(name string, controlPlane, ready bool) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: cluster.Name,
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: cluster.Name,
+			},
+		}
+		if controlPlane {
+			m.Labels[clusterv1.MachineControlPlaneLabelName] = ""
+		}
+		if ready {
+			conditions.MarkTrue(m, clusterv1.ReadyCondition)
+		} else {
+			conditions.MarkFalse(m, clusterv1.ReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, "")
+		}
+		return m
+	}
+
+	cp1 := newMachine("controlplane-1", true, true)
+	cp2 := newMachine("controlplane-2", true, false)
+	worker := newMachine("worker", false, false)
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, cluster, cp1, cp2, worker)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	// Not all the control plane Machines are ready.
+	g.Expect(r.reconcileControlPlane(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneReadyCondition)).To(Equal(clusterv1.WaitingForControlPlaneMachinesReason))
+	g.Expect(conditions.GetMessage(cluster, clusterv1.ControlPlaneReadyCondition)).To(Equal("1 of 2 control plane Machines are ready"))
+
+	// All the control plane Machines are ready; worker Machines are not taken into account.
+	conditions.MarkTrue(cp2, clusterv1.ReadyCondition)
+	g.Expect(c.Update(ctx, cp2)).To(Succeed())
+
+	g.Expect(r.reconcileControlPlane(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+}
+
+func TestClusterReconciler_reconcileControlPlaneProviderNotInstalled(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	// The CRD is listed, but its kind is not served by the API server.
+	crd := external.TestGenericInfrastructureCRD.DeepCopy()
+	crd.Name = "unregisteredcontrolplanes.controlplane.cluster.x-k8s.io"
+	crd.Spec.Group = "controlplane.cluster.x-k8s.io"
+	crd.Spec.Names.Kind = "UnregisteredControlPlane"
+	crd.Spec.Names.Plural = "unregisteredcontrolplanes"
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+				Kind:       "UnregisteredControlPlane",
+				Name:       "test",
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, crd, cluster),
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+		ExternalGetter: ExternalGetterFunc(func(_ context.Context, _ client.Client, ref *corev1.ObjectReference, _ string) (*unstructured.Unstructured, error) {
+			return nil, errors.Wrapf(&meta.NoKindMatchError{GroupKind: ref.GroupVersionKind().GroupKind(), SearchedVersions: []string{"v1alpha3"}},
+				"failed to retrieve %s external object %q/%q", ref.Kind, ref.Namespace, ref.Name)
+		}),
+	}
+
+	err := r.reconcileControlPlane(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("control plane provider for kind UnregisteredControlPlane"))
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneReadyCondition)).To(Equal(clusterv1.ProviderNotInstalledReason))
+	g.Expect(conditions.GetMessage(cluster, clusterv1.ControlPlaneReadyCondition)).To(ContainSubstring("UnregisteredControlPlane"))
+}
+
+func TestClusterReconciler_reconcileInfrastructureInOtherNamespace(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+				Namespace:  "shared-infrastructure",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "shared-infrastructure",
+			},
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+	g.Expect(cluster.Spec.ControlPlaneEndpoint.Host).To(Equal("1.2.3.4"))
+
+	// Cross-namespace owner references are not allowed.
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+	obj.SetKind("InfrastructureMachine")
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "shared-infrastructure", Name: "test"}, obj)).To(Succeed())
+	g.Expect(obj.GetOwnerReferences()).To(BeEmpty())
+	g.Expect(obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterLabelName, cluster.Name))
+}
+
+func TestClusterReconciler_reconcileInfrastructureClusterScoped(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	crd := external.TestGenericInfrastructureCRD.DeepCopy()
+	crd.Spec.Scope = apiextensionsv1.ClusterScoped
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name": "test",
+			},
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, crd, cluster, infraConfig),
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	g.Expect(r.refNamespace(cluster, cluster.Spec.InfrastructureRef, crd)).To(BeEmpty())
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+	g.Expect(cluster.Spec.ControlPlaneEndpoint.Host).To(Equal("1.2.3.4"))
+}
+
+func TestClusterReconciler_reconcileInfrastructureAPIVersionOverride(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	crd := external.TestGenericInfrastructureCRD.DeepCopy()
+	crd.Spec.Versions = append(crd.Spec.Versions,
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha4", Served: true},
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha5", Served: false},
+	)
+
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		wantErr        bool
+		wantAPIVersion string
+	}{
+		{
+			name:           "no annotation, should get the referenced API version",
+			wantAPIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+		},
+		{
+			name:           "annotation with a served version, should get the annotated API version",
+			annotations:    map[string]string{clusterv1.InfraAPIVersionAnnotation: "infrastructure.cluster.x-k8s.io/v1alpha4"},
+			wantAPIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+		},
+		{
+			name:        "annotation with a version not served, should return error",
+			annotations: map[string]string{clusterv1.InfraAPIVersionAnnotation: "infrastructure.cluster.x-k8s.io/v1alpha5"},
+			wantErr:     true,
+		},
+		{
+			name:        "annotation with a different group, should return error",
+			annotations: map[string]string{clusterv1.InfraAPIVersionAnnotation: "other.cluster.x-k8s.io/v1alpha4"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster",
+					Namespace:   "test-namespace",
+					Annotations: tt.annotations,
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			// The infrastructure object is served only at the expected API version.
+			apiVersion := "infrastructure.cluster.x-k8s.io/v1alpha3"
+			if tt.wantAPIVersion != "" {
+				apiVersion = tt.wantAPIVersion
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": apiVersion,
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+				},
+			}
+
+			var gotAPIVersion string
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme, crd.DeepCopy(), cluster, infraConfig),
+				Log:    log.Log,
+				scheme: scheme.Scheme,
+				ExternalGetter: ExternalGetterFunc(func(ctx context.Context, c client.Client, ref *corev1.ObjectReference, namespace string) (*unstructured.Unstructured, error) {
+					gotAPIVersion = ref.APIVersion
+					return external.Get(ctx, c, ref, namespace)
+				}),
+			}
+
+			err := r.reconcileInfrastructure(ctx, cluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(gotAPIVersion).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(gotAPIVersion).To(Equal(tt.wantAPIVersion))
+
+			// The reference itself is not changed.
+			g.Expect(cluster.Spec.InfrastructureRef.APIVersion).To(Equal("infrastructure.cluster.x-k8s.io/v1alpha3"))
+		})
+	}
+}
+
+// crdListCountingClient counts the List calls for CustomResourceDefinitions.
+type crdListCountingClient struct {
+	client.Client
+	crdLists int
+}
+
+func (c *crdListCountingClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if _, ok := list.(*apiextensionsv1.CustomResourceDefinitionList); ok {
+		c.crdLists++
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestClusterReconciler_reconcileExternalStorageVersion(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	// The CRD serves two versions, and the contract label points to the version which is not the storage one.
+	crd := external.TestGenericInfrastructureCRD.DeepCopy()
+	crd.Spec.Versions[0].Storage = false
+	crd.Spec.Versions = append(crd.Spec.Versions,
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1alpha4", Served: true, Storage: true},
+	)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	// The infrastructure object is served only in the storage version.
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			},
+		},
+	}
+
+	c := &crdListCountingClient{Client: fake.NewFakeClientWithScheme(scheme.Scheme, crd, cluster, infraConfig)}
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	out, err := r.reconcileExternal(ctx, cluster, cluster.Spec.InfrastructureRef)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.Result.GetAPIVersion()).To(Equal("infrastructure.cluster.x-k8s.io/v1alpha4"))
+
+	// The CRD is listed once for converting the reference to the contract version, and once for resolving
+	// both the API version and the namespace to use.
+	g.Expect(c.crdLists).To(Equal(2))
+}
+
+func TestClusterReconciler_computeReadiness(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newExternal := func(name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "InfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "test-namespace",
+				},
+				"spec": map[string]interface{}{
+					"controlPlaneEndpoint": map[string]interface{}{
+						"host": "1.2.3.4",
+						"port": int64(6443),
+					},
+				},
+				"status": map[string]interface{}{
+					"ready":       true,
+					"initialized": true,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		controlPlaneName string
+		wantGets         []string
+	}{
+		{
+			name:             "distinct references, each object is read once",
+			controlPlaneName: "control-plane",
+			wantGets:         []string{"infrastructure", "control-plane"},
+		},
+		{
+			name:             "identical references, the object is read once",
+			controlPlaneName: "infrastructure",
+			wantGets:         []string{"infrastructure"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "infrastructure",
+					},
+					ControlPlaneRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       tt.controlPlaneName,
+					},
+				},
+			}
+
+			var gets []string
+			r := &ClusterReconciler{
+				Client: helpers.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster,
+					newExternal("infrastructure"), newExternal("control-plane")),
+				Log:      log.Log,
+				scheme:   scheme.Scheme,
+				recorder: record.NewFakeRecorder(32),
+				ExternalGetter: ExternalGetterFunc(func(ctx context.Context, c client.Client, ref *corev1.ObjectReference, namespace string) (*unstructured.Unstructured, error) {
+					gets = append(gets, ref.Name)
+					return external.Get(ctx, c, ref, namespace)
+				}),
+			}
+
+			// Errors from the phases not under test, e.g. the kubeconfig one, are irrelevant here.
+			_, _ = r.reconcile(ctx, cluster)
+
+			// Both conditions are derived from the objects read once by computeReadiness.
+			g.Expect(gets).To(Equal(tt.wantGets))
+			g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+			g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+			g.Expect(cluster.Status.ControlPlaneReady).To(BeTrue())
+			g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureConditionsToMirror(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newCondition := func(t clusterv1.ConditionType, status corev1.ConditionStatus, reason string) map[string]interface{} {
+		c := map[string]interface{}{
+			"type":               string(t),
+			"status":             string(status),
+			"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+		}
+		if status == corev1.ConditionFalse {
+			c["reason"] = reason
+			c["severity"] = string(clusterv1.ConditionSeverityWarning)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name               string
+		conditionsToMirror []clusterv1.ConditionType
+		wantReady          bool
+	}{
+		{
+			name:      "without an allowlist the Ready condition is mirrored as is",
+			wantReady: false,
+		},
+		{
+			name:               "with an allowlist only allowlisted conditions are taken into account",
+			conditionsToMirror: []clusterv1.ConditionType{"LoadBalancerReady"},
+			wantReady:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+					"status": map[string]interface{}{
+						"conditions": []interface{}{
+							newCondition(clusterv1.ReadyCondition, corev1.ConditionFalse, "NoisyCheckFailed"),
+							newCondition("NoisyCheck", corev1.ConditionFalse, "NoisyCheckFailed"),
+							newCondition("LoadBalancerReady", corev1.ConditionTrue, ""),
+						},
+					},
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client:             fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+				Log:                log.Log,
+				scheme:             scheme.Scheme,
+				ConditionsToMirror: tt.conditionsToMirror,
+			}
+
+			g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+			g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(tt.wantReady))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      map[string]interface{}
+		wantFailure bool
+	}{
+		{
+			name: "terminal failures of the infrastructure object are surfaced on the Cluster",
+			status: map[string]interface{}{
+				"failureReason":  "InvalidConfiguration",
+				"failureMessage": "invalid region",
+			},
+			wantFailure: true,
+		},
+		{
+			name: "transient conditions of the infrastructure object are not surfaced as failures",
+			status: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               string(clusterv1.ReadyCondition),
+						"status":             string(corev1.ConditionFalse),
+						"reason":             "LoadBalancerProvisioningFailed",
+						"severity":           string(clusterv1.ConditionSeverityWarning),
+						"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+			g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+					"status": tt.status,
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+				Log:    log.Log,
+				scheme: scheme.Scheme,
+			}
+
+			g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+			r.reconcilePhase(ctx, cluster)
+			if !tt.wantFailure {
+				g.Expect(cluster.Status.FailureReason).To(BeNil())
+				g.Expect(cluster.Status.FailureMessage).To(BeNil())
+				g.Expect(cluster.Status.GetTypedPhase()).NotTo(Equal(clusterv1.ClusterPhaseFailed))
+				return
+			}
+			g.Expect(cluster.Status.FailureReason).NotTo(BeNil())
+			g.Expect(string(*cluster.Status.FailureReason)).To(Equal("InvalidConfiguration"))
+			g.Expect(cluster.Status.FailureMessage).NotTo(BeNil())
+			g.Expect(*cluster.Status.FailureMessage).To(ContainSubstring("invalid region"))
+			g.Expect(cluster.Status.GetTypedPhase()).To(Equal(clusterv1.ClusterPhaseFailed))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureFailureRecovery(t *testing.T) {
+	tests := []struct {
+		name                    string
+		clearFailuresOnRecovery bool
+		wantPhase               clusterv1.ClusterPhase
+	}{
+		{
+			name:      "failures are terminal by default",
+			wantPhase: clusterv1.ClusterPhaseFailed,
+		},
+		{
+			name:                    "failures are cleared once the infrastructure object recovers with ClearFailuresOnRecovery",
+			clearFailuresOnRecovery: true,
+			wantPhase:               clusterv1.ClusterPhaseProvisioning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+			g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+					"status": map[string]interface{}{
+						"failureReason":  "InvalidConfiguration",
+						"failureMessage": "invalid region",
+					},
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client:                  fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+				Log:                     log.Log,
+				scheme:                  scheme.Scheme,
+				ClearFailuresOnRecovery: tt.clearFailuresOnRecovery,
+			}
+
+			g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+			r.reconcilePhase(ctx, cluster)
+			g.Expect(cluster.Status.GetTypedPhase()).To(Equal(clusterv1.ClusterPhaseFailed))
+
+			// The failure fields of the infrastructure object are cleared.
+			g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: "test-namespace", Name: "test"}, infraConfig)).To(Succeed())
+			unstructured.RemoveNestedField(infraConfig.Object, "status", "failureReason")
+			unstructured.RemoveNestedField(infraConfig.Object, "status", "failureMessage")
+			g.Expect(r.Client.Update(ctx, infraConfig)).To(Succeed())
+
+			g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+			r.reconcilePhase(ctx, cluster)
+			g.Expect(cluster.Status.GetTypedPhase()).To(Equal(tt.wantPhase))
+			if tt.clearFailuresOnRecovery {
+				g.Expect(cluster.Status.FailureReason).To(BeNil())
+				g.Expect(cluster.Status.FailureMessage).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureMirroredConditionSeverity(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               string(clusterv1.ReadyCondition),
+						"status":             string(corev1.ConditionFalse),
+						"reason":             "LoadBalancerDeletionFailed",
+						"severity":           string(clusterv1.ConditionSeverityError),
+						"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+		MirroredConditionSeverity: func(_ *clusterv1.Cluster, severity clusterv1.ConditionSeverity) clusterv1.ConditionSeverity {
+			if severity == clusterv1.ConditionSeverityError {
+				return clusterv1.ConditionSeverityWarning
+			}
+			return severity
+		},
+	}
+
+	// The error reported by the infrastructure object is mirrored as a warning.
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal("LoadBalancerDeletionFailed"))
+	g.Expect(*conditions.GetSeverity(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.ConditionSeverityWarning))
+}
+
+func TestClusterReconciler_reconcileInfrastructureMirroredConditionMissing(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test-namespace",
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               string(clusterv1.ReadyCondition),
+						"status":             string(corev1.ConditionTrue),
+						"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+					},
+					map[string]interface{}{
+						"type":               "LoadBalancerReady",
+						"status":             string(corev1.ConditionTrue),
+						"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+
+	// The provider removes the Ready condition, while still reporting other conditions.
+	g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: "test-namespace", Name: "test"}, infraConfig)).To(Succeed())
+	infraConditions, _, err := unstructured.NestedSlice(infraConfig.Object, "status", "conditions")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(unstructured.SetNestedSlice(infraConfig.Object, infraConditions[1:], "status", "conditions")).To(Succeed())
+	g.Expect(r.Client.Update(ctx, infraConfig)).To(Succeed())
+
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsUnknown(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.MirroredConditionMissingReason))
+}
+
+func TestClusterReconciler_reconcileLegacyLabels(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newCluster := func(annotations map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-cluster",
+				Namespace:   "test-namespace",
+				Annotations: annotations,
+			},
+		}
+	}
+	newLegacyMachine := func() *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "legacy-machine",
+				Namespace: "test-namespace",
+				Labels: map[string]string{
+					clusterv1.LegacyClusterLabelName: "test-cluster",
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: "test-cluster",
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		cluster   *clusterv1.Cluster
+		wantLabel bool
+	}{
+		{
+			name:      "annotation not set, should not add the cluster label",
+			cluster:   newCluster(nil),
+			wantLabel: false,
+		},
+		{
+			name:      "annotation set, should add the cluster label",
+			cluster:   newCluster(map[string]string{clusterv1.NormalizeLegacyLabelsAnnotation: ""}),
+			wantLabel: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := newLegacyMachine()
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, tt.cluster, machine)
+			r := &ClusterReconciler{
+				Client: c,
+				Log:    log.Log,
+			}
+
+			g.Expect(r.reconcileLegacyLabels(ctx, tt.cluster)).To(Succeed())
+
+			got := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, util.ObjectKey(machine), got)).To(Succeed())
+			if tt.wantLabel {
+				g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "test-cluster"))
+			} else {
+				g.Expect(got.Labels).NotTo(HaveKey(clusterv1.ClusterLabelName))
+			}
+			g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.LegacyClusterLabelName, "test-cluster"))
+		})
+	}
+}
+
+// mustListDescendants lists the descendants of the Cluster, as listed once per reconcile for the phases acting on them.
+func mustListDescendants(g *WithT, r *ClusterReconciler, cluster *clusterv1.Cluster) *clusterDescendants {
+	descendants, err := r.listDescendants(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	return &descendants
+}
+
+func TestClusterReconciler_reconcileDescendantOwnerReferences(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+	machine := newMachineBuilder().named("machine").inCluster(cluster).ownedBy(cluster).ownedBy(cluster).build()
+	g.Expect(machine.OwnerReferences).To(HaveLen(2))
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &machine)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	g.Expect(r.reconcileDescendantOwnerReferences(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	got := &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&machine), got)).To(Succeed())
+	g.Expect(got.OwnerReferences).To(HaveLen(1))
+	g.Expect(util.IsOwnedByObject(got, cluster)).To(BeTrue())
+}
+
+func TestClusterReconciler_reconcileDescendantOwnerReferencesBlockOwnerDeletion(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).build()
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).ownedBy(cluster).build()
+	machine := newMachineBuilder().named("machine").inCluster(cluster).ownedBy(cluster).ownedByMachineSet(&ms).build()
+	g.Expect(md.OwnerReferences[0].BlockOwnerDeletion).To(BeNil())
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &ms, &md, &machine)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	g.Expect(r.reconcileDescendantOwnerReferences(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	gotMD := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&md), gotMD)).To(Succeed())
+	g.Expect(gotMD.OwnerReferences).To(HaveLen(1))
+	g.Expect(gotMD.OwnerReferences[0].BlockOwnerDeletion).NotTo(BeNil())
+	g.Expect(*gotMD.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+
+	// Only the Cluster owner reference is changed.
+	gotMachine := &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&machine), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.OwnerReferences).To(HaveLen(2))
+	for _, ref := range gotMachine.OwnerReferences {
+		if ref.Kind == "Cluster" {
+			g.Expect(ref.BlockOwnerDeletion).NotTo(BeNil())
+			g.Expect(*ref.BlockOwnerDeletion).To(BeTrue())
+			continue
+		}
+		g.Expect(ref.BlockOwnerDeletion).To(BeNil())
+	}
+}
+
+func TestClusterReconciler_reconcileUnmanagedControlPlaneMachines(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newCluster := func() *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneRef: &corev1.ObjectReference{
+					APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+					Kind:       "GenericControlPlane",
+					Name:       "test-control-plane",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		ownedByCluster bool
+		wantCondition  bool
+	}{
+		{
+			name:           "control plane machines owned by the Cluster, should raise a warning",
+			ownedByCluster: true,
+			wantCondition:  false,
+		},
+		{
+			name:           "control plane machines not owned by the Cluster",
+			ownedByCluster: false,
+			wantCondition:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newCluster()
+			builder := newMachineBuilder().named("control-plane").inCluster(cluster).controlPlane()
+			if tt.ownedByCluster {
+				builder = builder.ownedBy(cluster)
+			}
+			machine := builder.build()
+
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme, cluster, &machine),
+				Log:    log.Log,
+			}
+
+			g.Expect(r.reconcileUnmanagedControlPlaneMachines(ctx, cluster)).To(Succeed())
+			if tt.wantCondition {
+				g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneMachinesManagedCondition)).To(BeTrue())
+				return
+			}
+			g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneMachinesManagedCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneMachinesManagedCondition)).To(Equal(clusterv1.UnmanagedControlPlaneMachinesReason))
+			g.Expect(conditions.Get(cluster, clusterv1.ControlPlaneMachinesManagedCondition).Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileDescendantOwnerReferencesMachinePool(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	g.Expect(feature.MutableGates.Set("MachinePool=true")).To(Succeed())
+	defer func() {
+		g.Expect(feature.MutableGates.Set("MachinePool=false")).To(Succeed())
+	}()
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			UID:       "test-uid",
+		},
+	}
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mp",
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+			},
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: cluster.Name,
+		},
+	}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, mp)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	g.Expect(r.reconcileDescendantOwnerReferences(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	got := &expv1.MachinePool{}
+	g.Expect(c.Get(ctx, util.ObjectKey(mp), got)).To(Succeed())
+	g.Expect(util.IsOwnedByObject(got, cluster)).To(BeTrue())
+	g.Expect(got.OwnerReferences).To(HaveLen(1))
+	g.Expect(got.OwnerReferences[0].UID).To(Equal(cluster.UID))
+
+	// The repaired MachinePool is now a direct descendant of the Cluster.
+	descendants, err := listDescendants(ctx, c, cluster, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(descendants.ownedLength(cluster)).To(Equal(1))
+}
+
+func TestClusterReconciler_reconcileDescendantLabels(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				"foo":                      "bar",
+				"owner":                    "cluster",
+				clusterv1.ClusterLabelName: "test-cluster",
+			},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).build()
+	md.Labels["owner"] = "md"
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &md)
+	r := &ClusterReconciler{
+		Client:          c,
+		Log:             log.Log,
+		PropagateLabels: true,
+	}
+
+	// The Cluster labels are propagated, without taking over the labels defined on the MachineDeployment.
+	g.Expect(r.reconcileDescendantLabels(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	got := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&md), got)).To(Succeed())
+	g.Expect(got.Labels).To(HaveKeyWithValue("foo", "bar"))
+	g.Expect(got.Labels).To(HaveKeyWithValue("owner", "md"))
+	g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.PropagatedLabelsAnnotation, "foo"))
+
+	// Removing the label from the Cluster removes it from the MachineDeployment too.
+	delete(cluster.Labels, "foo")
+	g.Expect(r.reconcileDescendantLabels(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	got = &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&md), got)).To(Succeed())
+	g.Expect(got.Labels).NotTo(HaveKey("foo"))
+	g.Expect(got.Labels).To(HaveKeyWithValue("owner", "md"))
+	g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "test-cluster"))
+	g.Expect(got.Annotations).NotTo(HaveKey(clusterv1.PropagatedLabelsAnnotation))
+}
+
+func TestClusterReconciler_reconcileClusterLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		wantLabels map[string]string
+	}{
+		{
+			name:       "cluster without labels, should add the cluster label",
+			wantLabels: map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+		},
+		{
+			name:   "cluster with other labels, should add the cluster label",
+			labels: map[string]string{"foo": "bar"},
+			wantLabels: map[string]string{
+				"foo":                      "bar",
+				clusterv1.ClusterLabelName: "test-cluster",
+			},
+		},
+		{
+			name:       "cluster label already set by users, should not be changed",
+			labels:     map[string]string{clusterv1.ClusterLabelName: "custom"},
+			wantLabels: map[string]string{clusterv1.ClusterLabelName: "custom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+					Labels:    tt.labels,
+				},
+			}
+			r := &ClusterReconciler{
+				Log: log.Log,
+			}
+
+			g.Expect(r.reconcileClusterLabel(ctx, cluster)).To(Succeed())
+			g.Expect(cluster.Labels).To(Equal(tt.wantLabels))
+
+			// Reconciling again should not change the labels.
+			g.Expect(r.reconcileClusterLabel(ctx, cluster)).To(Succeed())
+			g.Expect(cluster.Labels).To(Equal(tt.wantLabels))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileInfrastructureAdopt(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			Annotations: map[string]string{
+				clusterv1.AdoptInfrastructureAnnotation: "existing",
+			},
+		},
+	}
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "existing",
+				"namespace": "test-namespace",
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(),
+		external.TestGenericInfrastructureTemplateCRD.DeepCopy(), cluster)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+		scheme: scheme.Scheme,
+	}
+
+	// The Cluster waits for the infrastructure object to be adopted to exist.
+	err := r.reconcileInfrastructure(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	_, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(cluster.Spec.InfrastructureRef).To(BeNil())
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.WaitingForInfrastructureFallbackReason))
+
+	// Once it exists, the infrastructure object is referenced, owned and its state mirrored into the Cluster.
+	g.Expect(c.Create(ctx, infraConfig)).To(Succeed())
+	g.Expect(r.reconcileInfrastructure(ctx, cluster)).To(Succeed())
+	g.Expect(cluster.Spec.InfrastructureRef).To(Equal(&corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+		Kind:       "InfrastructureMachine",
+		Name:       "existing",
+		Namespace:  "test-namespace",
+	}))
+	g.Expect(cluster.Status.InfrastructureReady).To(BeTrue())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+	obj.SetKind("InfrastructureMachine")
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "test-namespace", Name: "existing"}, obj)).To(Succeed())
+	g.Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+	g.Expect(obj.GetOwnerReferences()[0].Kind).To(Equal("Cluster"))
+	g.Expect(obj.GetOwnerReferences()[0].Name).To(Equal(cluster.Name))
+}
+
+func TestClusterReconciler_reconcileDescendantClusterUIDLabel(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	// The Cluster has been recreated with the same name as a previous one.
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			UID:       "new-uid",
+		},
+	}
+	ownerRef := func(uid types.UID) metav1.OwnerReference {
+		return metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       cluster.Name,
+			UID:        uid,
+		}
+	}
+
+	staleMD := newMachineDeploymentBuilder().named("md-stale").inCluster(cluster).build()
+	staleMD.Labels[clusterv1.ClusterUIDLabelName] = "old-uid"
+	staleMD.OwnerReferences = []metav1.OwnerReference{ownerRef("old-uid")}
+
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).build()
+	md.OwnerReferences = []metav1.OwnerReference{ownerRef(cluster.UID)}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &staleMD, &md)
+	r := &ClusterReconciler{
+		Client:                        c,
+		Log:                           log.Log,
+		FilterDescendantsByClusterUID: true,
+	}
+
+	descendants, err := r.listDescendants(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(r.reconcileDescendantClusterUIDLabel(ctx, cluster, &descendants)).To(Succeed())
+
+	// Only the descendants owned by the current Cluster are labeled with its UID.
+	got := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&md), got)).To(Succeed())
+	g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterUIDLabelName, "new-uid"))
+
+	got = &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&staleMD), got)).To(Succeed())
+	g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterUIDLabelName, "old-uid"))
+
+	// The descendants of the previous Cluster are not considered descendants of the current one.
+	descendants, err = r.listDescendants(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(descendants.machineDeployments.Items).To(HaveLen(1))
+	g.Expect(descendants.machineDeployments.Items[0].Name).To(Equal("md"))
+}
+
+func TestClusterReconciler_reconcileDescendantsLimit(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+	md1 := newMachineDeploymentBuilder().named("md1").inCluster(cluster).build()
+	md2 := newMachineDeploymentBuilder().named("md2").inCluster(cluster).build()
+
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client:                      fake.NewFakeClientWithScheme(scheme.Scheme, cluster, &md1, &md2),
+		Log:                         log.Log,
+		recorder:                    recorder,
+		MaxDescendantsWarnThreshold: 2,
+	}
+
+	// The number of descendants is within the threshold.
+	g.Expect(r.reconcileDescendantsLimit(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.DescendantsWithinLimitCondition)).To(BeTrue())
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// Exceeding the threshold surfaces a Warning event and an informational condition.
+	r.MaxDescendantsWarnThreshold = 1
+	g.Expect(r.reconcileDescendantsLimit(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.DescendantsWithinLimitCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.DescendantsWithinLimitCondition)).To(Equal(clusterv1.TooManyDescendantsReason))
+	g.Expect(*conditions.GetSeverity(cluster, clusterv1.DescendantsWithinLimitCondition)).To(Equal(clusterv1.ConditionSeverityInfo))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("TooManyDescendants")))
+
+	// The event is not emitted again while the threshold is still exceeded.
+	g.Expect(r.reconcileDescendantsLimit(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(recorder.Events).To(BeEmpty())
+}
+
+func TestClusterReconciler_reconcileDescendantTopology(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).build()
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).build()
+	ms.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&md, clusterv1.GroupVersion.WithKind("MachineDeployment"))}
+	worker1 := newMachineBuilder().named("worker-1").inCluster(cluster).build()
+	worker2 := newMachineBuilder().named("worker-2").inCluster(cluster).build()
+	controlPlane := newMachineBuilder().named("control-plane").inCluster(cluster).controlPlane().build()
+
+	r := &ClusterReconciler{
+		Client:                   fake.NewFakeClientWithScheme(scheme.Scheme, cluster, &md, &ms, &worker1, &worker2, &controlPlane),
+		Log:                      log.Log,
+		ExportDescendantTopology: true,
+	}
+
+	g.Expect(r.reconcileDescendantTopology(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(cluster.Annotations).To(HaveKey(clusterv1.DescendantTopologyAnnotation))
+
+	topology := descendantTopology{}
+	g.Expect(json.Unmarshal([]byte(cluster.Annotations[clusterv1.DescendantTopologyAnnotation]), &topology)).To(Succeed())
+	g.Expect(topology.Counts).To(Equal(map[string]int{
+		"MachineDeployment": 1,
+		"MachineSet":        1,
+		"Machine":           3,
+	}))
+	g.Expect(topology.Descendants).To(HaveLen(5))
+	g.Expect(topology.Descendants).To(ContainElement(descendantTopologyNode{Kind: "MachineSet", Name: "ms", Controller: "MachineDeployment/md"}))
+	g.Expect(topology.Truncated).To(BeFalse())
+
+	// The annotation is removed once the export is disabled.
+	r.ExportDescendantTopology = false
+	g.Expect(r.reconcileDescendantTopology(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(cluster.Annotations).NotTo(HaveKey(clusterv1.DescendantTopologyAnnotation))
+}
+
+func TestMarshalDescendantTopology(t *testing.T) {
+	g := NewWithT(t)
+
+	topology := descendantTopology{
+		Counts: map[string]int{"Machine": 100},
+	}
+	for i := 0; i < 100; i++ {
+		topology.Descendants = append(topology.Descendants, descendantTopologyNode{Kind: "Machine", Name: fmt.Sprintf("machine-%d", i)})
+	}
+
+	// The summary fits within the limit.
+	data, err := marshalDescendantTopology(topology, 16*1024)
+	g.Expect(err).NotTo(HaveOccurred())
+	got := descendantTopology{}
+	g.Expect(json.Unmarshal([]byte(data), &got)).To(Succeed())
+	g.Expect(got).To(Equal(topology))
+
+	// The trailing descendants are left out to fit within the limit, retaining the counts.
+	data, err = marshalDescendantTopology(topology, 1024)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(data)).To(BeNumerically("<=", 1024))
+	got = descendantTopology{}
+	g.Expect(json.Unmarshal([]byte(data), &got)).To(Succeed())
+	g.Expect(got.Truncated).To(BeTrue())
+	g.Expect(got.Counts).To(Equal(topology.Counts))
+	g.Expect(got.Descendants).NotTo(BeEmpty())
+	g.Expect(got.Descendants).To(Equal(topology.Descendants[:len(got.Descendants)]))
+
+	// Not even a single descendant fits within the limit.
+	data, err = marshalDescendantTopology(topology, 10)
+	g.Expect(err).NotTo(HaveOccurred())
+	got = descendantTopology{}
+	g.Expect(json.Unmarshal([]byte(data), &got)).To(Succeed())
+	g.Expect(got.Truncated).To(BeTrue())
+	g.Expect(got.Descendants).To(BeEmpty())
+}
+
+func TestClusterReconciler_reconcileNodeDrainTimeout(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			NodeDrainTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+	newMachine := func(name string, nodeDrainTimeout *metav1.Duration) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: cluster.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+				}},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:      cluster.Name,
+				NodeDrainTimeout: nodeDrainTimeout,
+			},
+		}
+	}
+	unset := newMachine("unset", nil)
+	custom := newMachine("custom", &metav1.Duration{Duration: time.Minute})
+	unowned := newMachine("unowned", nil)
+	unowned.OwnerReferences = nil
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, unset, custom, unowned)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	g.Expect(r.reconcileNodeDrainTimeout(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	// The Cluster node drain timeout flows to the Machines of the Cluster without their own, owned or not.
+	for _, m := range []*clusterv1.Machine{unset, unowned} {
+		got := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, util.ObjectKey(m), got)).To(Succeed())
+		g.Expect(got.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: 10 * time.Minute}))
+		g.Expect(got.Annotations).To(HaveKey(clusterv1.NodeDrainTimeoutInheritedAnnotation))
+	}
+
+	// Machines with their own node drain timeout are left untouched.
+	got := &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(custom), got)).To(Succeed())
+	g.Expect(got.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+	g.Expect(got.Annotations).NotTo(HaveKey(clusterv1.NodeDrainTimeoutInheritedAnnotation))
+
+	// Inherited node drain timeouts follow the changes of the Cluster one.
+	cluster.Spec.NodeDrainTimeout = &metav1.Duration{Duration: 20 * time.Minute}
+	g.Expect(r.reconcileNodeDrainTimeout(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	for _, m := range []*clusterv1.Machine{unset, unowned} {
+		got := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, util.ObjectKey(m), got)).To(Succeed())
+		g.Expect(got.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: 20 * time.Minute}))
+	}
+	got = &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(custom), got)).To(Succeed())
+	g.Expect(got.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+
+	// Once the Cluster node drain timeout is unset, inherited node drain timeouts are removed.
+	cluster.Spec.NodeDrainTimeout = nil
+	g.Expect(r.reconcileNodeDrainTimeout(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	for _, m := range []*clusterv1.Machine{unset, unowned} {
+		got := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, util.ObjectKey(m), got)).To(Succeed())
+		g.Expect(got.Spec.NodeDrainTimeout).To(BeNil())
+		g.Expect(got.Annotations).NotTo(HaveKey(clusterv1.NodeDrainTimeoutInheritedAnnotation))
+	}
+	got = &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(custom), got)).To(Succeed())
+	g.Expect(got.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+}
+
+func TestClusterReconciler_reconcileMachineHealthChecksDisabled(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+		Spec: clusterv1.ClusterSpec{
+			DisableMachineHealthChecks: pointer.BoolPtr(true),
+		},
+	}
+	// The worker Machine is owned by a MachineSet, as the Machines remediated by MachineHealthChecks are.
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).ownedBy(cluster).build()
+	worker := newMachineBuilder().named("worker").inCluster(cluster).ownedByMachineSet(&ms).build()
+	skipped := newMachineBuilder().named("skipped").inCluster(cluster).ownedByMachineSet(&ms).build()
+	skipped.Annotations = map[string]string{clusterv1.SkipRemediationAnnotation: "maintenance"}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &ms, &worker, &skipped)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	// The worker Machines of the Cluster are annotated to skip remediation, and so is the Cluster.
+	g.Expect(r.reconcileMachineHealthChecksDisabled(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(cluster.Annotations).To(HaveKeyWithValue(clusterv1.SkipRemediationAnnotation, cluster.Name))
+
+	got := &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&worker), got)).To(Succeed())
+	g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.SkipRemediationAnnotation, cluster.Name))
+
+	// The annotated Machine is not remediated by MachineHealthChecks.
+	target := &healthCheckTarget{
+		Machine: got,
+		MHC:     newTestMachineHealthCheck("mhc", cluster.Namespace, cluster.Name, map[string]string{}),
+	}
+	g.Expect(target.remediate(ctx, log.Log, c, record.NewFakeRecorder(1))).To(Succeed())
+	g.Expect(c.Get(ctx, util.ObjectKey(&worker), &clusterv1.Machine{})).To(Succeed())
+
+	// Once re-enabled, only the annotations set by the Cluster are removed.
+	cluster.Spec.DisableMachineHealthChecks = nil
+	g.Expect(r.reconcileMachineHealthChecksDisabled(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+	g.Expect(cluster.Annotations).NotTo(HaveKey(clusterv1.SkipRemediationAnnotation))
+
+	got = &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&worker), got)).To(Succeed())
+	g.Expect(got.Annotations).NotTo(HaveKey(clusterv1.SkipRemediationAnnotation))
+
+	got = &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&skipped), got)).To(Succeed())
+	g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.SkipRemediationAnnotation, "maintenance"))
+
+	// Without the annotation on the Cluster, the Machines are left untouched.
+	got = &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&worker), got)).To(Succeed())
+	got.Annotations = map[string]string{clusterv1.SkipRemediationAnnotation: cluster.Name}
+	g.Expect(c.Update(ctx, got)).To(Succeed())
+	g.Expect(r.reconcileMachineHealthChecksDisabled(ctx, cluster, mustListDescendants(g, r, cluster))).To(Succeed())
+
+	got = &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, util.ObjectKey(&worker), got)).To(Succeed())
+	g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.SkipRemediationAnnotation, cluster.Name))
+}
+
+func TestClusterReconciler_reconcileInfrastructureReadyTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		createdAgo  time.Duration
+		wantReason  string
+		wantRequeue time.Duration
+	}{
+		{
+			name:        "timeout not exceeded, should wait for the infrastructure until the timeout",
+			createdAgo:  10 * time.Minute,
+			wantReason:  clusterv1.WaitingForInfrastructureFallbackReason,
+			wantRequeue: 50 * time.Minute,
+		},
+		{
+			name:        "timeout exceeded, should report the timeout and keep waiting for the infrastructure",
+			createdAgo:  2 * time.Hour,
+			wantReason:  clusterv1.TimedOutReason,
+			wantRequeue: infrastructureReadyTimedOutRequeueAfter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+			g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cluster",
+					Namespace:         "test-namespace",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-tt.createdAgo)),
+				},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+						Kind:       "InfrastructureMachine",
+						Name:       "test",
+					},
+					InfrastructureReadyTimeout: &metav1.Duration{Duration: time.Hour},
+				},
+			}
+			infraConfig := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureMachine",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test-namespace",
+					},
+					"status": map[string]interface{}{
+						"ready": false,
+					},
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, infraConfig),
+				Log:    log.Log,
+				scheme: scheme.Scheme,
+			}
+
+			err := r.reconcileInfrastructure(ctx, cluster)
+			g.Expect(err).To(HaveOccurred())
+			requeueErr, ok := errors.Cause(err).(capierrors.HasRequeueAfterError)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(requeueErr.GetRequeueAfter()).To(BeNumerically("~", tt.wantRequeue, time.Minute))
+
+			g.Expect(cluster.Status.InfrastructureReady).To(BeFalse())
+			g.Expect(conditions.IsFalse(cluster, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(tt.wantReason))
 		})
 	}
 }
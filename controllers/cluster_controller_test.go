@@ -18,29 +18,47 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/secret"
 )
 
 var _ = Describe("Cluster Reconciler", func() {
@@ -246,6 +264,51 @@ var _ = Describe("Cluster Reconciler", func() {
 		}, timeout).Should(BeEmpty())
 	})
 
+	It("Should restore the Kubeconfig Secret if it is edited", func() {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-kubeconfig-",
+				Namespace:    v1.NamespaceDefault,
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443},
+			},
+		}
+
+		Expect(testEnv.Create(ctx, cluster)).To(Succeed())
+		defer func() {
+			err := testEnv.Delete(ctx, cluster)
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		certificates := secret.Certificates{&secret.Certificate{Purpose: secret.ClusterCA}}
+		Expect(certificates.Generate()).To(Succeed())
+		caSecret := certificates.GetByPurpose(secret.ClusterCA).AsSecret(util.ObjectKey(cluster), metav1.OwnerReference{})
+		Expect(testEnv.Create(ctx, caSecret)).To(Succeed())
+		defer func() {
+			err := testEnv.Delete(ctx, caSecret)
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		// Wait for the Kubeconfig Secret to be generated.
+		key := client.ObjectKey{Namespace: cluster.Namespace, Name: secret.Name(cluster.Name, secret.Kubeconfig)}
+		configSecret := &corev1.Secret{}
+		Eventually(func() error {
+			return testEnv.Get(ctx, key, configSecret)
+		}, timeout).Should(Succeed())
+
+		// Edit the Kubeconfig Secret and expect it to be restored.
+		configSecret.Data[secret.KubeconfigDataName] = []byte("tampered")
+		Expect(testEnv.Update(ctx, configSecret)).To(Succeed())
+
+		Eventually(func() error {
+			if err := testEnv.Get(ctx, key, configSecret); err != nil {
+				return err
+			}
+			return kubeconfig.VerifySecret(ctx, testEnv, util.ObjectKey(cluster), cluster.Spec.ControlPlaneEndpoint.String(), configSecret)
+		}, timeout).Should(Succeed())
+	})
+
 	It("Should successfully set Status.ControlPlaneInitialized on the cluster object if controlplane is ready", func() {
 		cluster := &clusterv1.Cluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -614,6 +677,46 @@ func TestClusterReconciler(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("multiple controlplane machines for the same cluster, should return the cluster once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test",
+			},
+		}
+		newControlPlaneMachine := func(name string) *clusterv1.Machine {
+			return &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "test",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName:             cluster.Name,
+						clusterv1.MachineControlPlaneLabelName: "",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: cluster.Name,
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &v1.ObjectReference{Kind: "Node", Name: name},
+				},
+			}
+		}
+		m1 := newControlPlaneMachine("controlplane-1")
+		m2 := newControlPlaneMachine("controlplane-2")
+
+		g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+		r := &ClusterReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, cluster, m1, m2),
+			Log:    log.Log,
+		}
+		g.Expect(r.controlPlaneMachinesToClusters(m1, m2)).To(Equal([]ctrl.Request{
+			{NamespacedName: util.ObjectKey(cluster)},
+		}))
+	})
 }
 
 type machineDeploymentBuilder struct {
@@ -638,6 +741,15 @@ func (b *machineDeploymentBuilder) ownedBy(c *clusterv1.Cluster) *machineDeploym
 	return b
 }
 
+func (b *machineDeploymentBuilder) inCluster(c *clusterv1.Cluster) *machineDeploymentBuilder {
+	b.md.Namespace = c.Namespace
+	if b.md.Labels == nil {
+		b.md.Labels = map[string]string{}
+	}
+	b.md.Labels[clusterv1.ClusterLabelName] = c.Name
+	return b
+}
+
 func (b *machineDeploymentBuilder) build() clusterv1.MachineDeployment {
 	return b.md
 }
@@ -664,6 +776,15 @@ func (b *machineSetBuilder) ownedBy(c *clusterv1.Cluster) *machineSetBuilder {
 	return b
 }
 
+func (b *machineSetBuilder) inCluster(c *clusterv1.Cluster) *machineSetBuilder {
+	b.ms.Namespace = c.Namespace
+	if b.ms.Labels == nil {
+		b.ms.Labels = map[string]string{}
+	}
+	b.ms.Labels[clusterv1.ClusterLabelName] = c.Name
+	return b
+}
+
 func (b *machineSetBuilder) build() clusterv1.MachineSet {
 	return b.ms
 }
@@ -690,8 +811,38 @@ func (b *machineBuilder) ownedBy(c *clusterv1.Cluster) *machineBuilder {
 	return b
 }
 
+func (b *machineBuilder) ownedByMachineSet(ms *clusterv1.MachineSet) *machineBuilder {
+	b.m.OwnerReferences = append(b.m.OwnerReferences, metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "MachineSet",
+		Name:       ms.Name,
+	})
+	return b
+}
+
+func (b *machineBuilder) ownedByMachinePool(mp *expv1.MachinePool) *machineBuilder {
+	b.m.OwnerReferences = append(b.m.OwnerReferences, metav1.OwnerReference{
+		APIVersion: expv1.GroupVersion.String(),
+		Kind:       "MachinePool",
+		Name:       mp.Name,
+	})
+	return b
+}
+
 func (b *machineBuilder) controlPlane() *machineBuilder {
-	b.m.Labels = map[string]string{clusterv1.MachineControlPlaneLabelName: ""}
+	if b.m.Labels == nil {
+		b.m.Labels = map[string]string{}
+	}
+	b.m.Labels[clusterv1.MachineControlPlaneLabelName] = ""
+	return b
+}
+
+func (b *machineBuilder) inCluster(c *clusterv1.Cluster) *machineBuilder {
+	b.m.Namespace = c.Namespace
+	if b.m.Labels == nil {
+		b.m.Labels = map[string]string{}
+	}
+	b.m.Labels[clusterv1.ClusterLabelName] = c.Name
 	return b
 }
 
@@ -779,6 +930,163 @@ func TestFilterOwnedDescendants(t *testing.T) {
 	g.Expect(actual).To(Equal(expected))
 }
 
+func TestEachOwnedDescendant(t *testing.T) {
+	g := NewWithT(t)
+
+	c := clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "c",
+		},
+	}
+
+	d := clusterDescendants{
+		machineDeployments: clusterv1.MachineDeploymentList{
+			Items: []clusterv1.MachineDeployment{
+				newMachineDeploymentBuilder().named("md1").ownedBy(&c).build(),
+				newMachineDeploymentBuilder().named("md2").build(),
+			},
+		},
+		machineSets: clusterv1.MachineSetList{
+			Items: []clusterv1.MachineSet{
+				newMachineSetBuilder().named("ms1").ownedBy(&c).build(),
+			},
+		},
+		controlPlaneMachines: clusterv1.MachineList{
+			Items: []clusterv1.Machine{
+				newMachineBuilder().named("m1").ownedBy(&c).controlPlane().build(),
+			},
+		},
+		workerMachines: clusterv1.MachineList{
+			Items: []clusterv1.Machine{
+				newMachineBuilder().named("m2").ownedBy(&c).build(),
+				newMachineBuilder().named("m3").build(),
+			},
+		},
+	}
+
+	// The callback is invoked for each owned descendant, across all the lists of descendants.
+	var names []string
+	g.Expect(d.eachOwnedDescendant(&c, func(o runtime.Object) error {
+		acc, err := meta.Accessor(o)
+		if err != nil {
+			return err
+		}
+		names = append(names, acc.GetName())
+		return nil
+	})).To(Succeed())
+	g.Expect(names).To(Equal([]string{"md1", "ms1", "m2", "m1"}))
+
+	// The iteration stops at the first error returned by the callback.
+	calls := 0
+	err := d.eachOwnedDescendant(&c, func(runtime.Object) error {
+		calls++
+		return errors.New("stop")
+	})
+	g.Expect(err).To(MatchError("stop"))
+	g.Expect(calls).To(Equal(1))
+}
+
+func TestSplitMachineList(t *testing.T) {
+	g := NewWithT(t)
+
+	c := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "c",
+		},
+	}
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mp1",
+		},
+	}
+
+	list := &clusterv1.MachineList{
+		Items: []clusterv1.Machine{
+			newMachineBuilder().named("cp1").inCluster(c).controlPlane().build(),
+			newMachineBuilder().named("w1").inCluster(c).build(),
+			newMachineBuilder().named("mp-machine1").inCluster(c).ownedByMachinePool(mp).build(),
+		},
+	}
+
+	// Machines backed by a MachinePool are accounted for by their MachinePool, not as workers.
+	controlPlanes, workers := splitMachineList(list)
+	g.Expect(controlPlanes.Items).To(HaveLen(1))
+	g.Expect(controlPlanes.Items[0].Name).To(Equal("cp1"))
+	g.Expect(workers.Items).To(HaveLen(1))
+	g.Expect(workers.Items[0].Name).To(Equal("w1"))
+}
+
+func TestClusterDescendantsOwnedLength(t *testing.T) {
+	g := NewWithT(t)
+
+	c := clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "c",
+		},
+	}
+
+	mp1OwnedByCluster := expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mp1",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       c.Name,
+				},
+			},
+		},
+	}
+	mp2NotOwnedByCluster := expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mp2",
+		},
+	}
+
+	d := clusterDescendants{
+		machineDeployments: clusterv1.MachineDeploymentList{
+			Items: []clusterv1.MachineDeployment{
+				newMachineDeploymentBuilder().named("md1").inCluster(&c).build(),
+				newMachineDeploymentBuilder().named("md2").inCluster(&c).ownedBy(&c).build(),
+			},
+		},
+		machineSets: clusterv1.MachineSetList{
+			Items: []clusterv1.MachineSet{
+				newMachineSetBuilder().named("ms1").inCluster(&c).build(),
+			},
+		},
+		controlPlaneMachines: clusterv1.MachineList{
+			Items: []clusterv1.Machine{
+				newMachineBuilder().named("m1").inCluster(&c).ownedBy(&c).controlPlane().build(),
+			},
+		},
+		workerMachines: clusterv1.MachineList{
+			Items: []clusterv1.Machine{
+				newMachineBuilder().named("m2").inCluster(&c).ownedBy(&c).build(),
+				newMachineBuilder().named("m3").inCluster(&c).build(),
+			},
+		},
+		machinePools: expv1.MachinePoolList{
+			Items: []expv1.MachinePool{
+				mp1OwnedByCluster,
+				mp2NotOwnedByCluster,
+			},
+		},
+	}
+
+	// Unowned descendants matching the cluster label are not counted.
+	g.Expect(d.length()).To(Equal(8))
+	g.Expect(d.ownedLength(&c)).To(Equal(4))
+}
+
 func TestReconcileControlPlaneInitializedControlPlaneRef(t *testing.T) {
 	g := NewWithT(t)
 
@@ -801,3 +1109,2665 @@ func TestReconcileControlPlaneInitializedControlPlaneRef(t *testing.T) {
 	g.Expect(r.reconcileControlPlaneInitialized(context.Background(), c)).To(Succeed())
 	g.Expect(c.Status.ControlPlaneInitialized).To(BeFalse())
 }
+
+func TestReconcileControlPlaneInitializedMachinePool(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	g.Expect(feature.MutableGates.Set("MachinePool=true")).To(Succeed())
+	defer func() {
+		g.Expect(feature.MutableGates.Set("MachinePool=false")).To(Succeed())
+	}()
+
+	c := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "c",
+			Namespace: "test",
+		},
+	}
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "control-plane",
+			Namespace: "test",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:             c.Name,
+				clusterv1.MachineControlPlaneLabelName: "",
+			},
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: c.Name,
+		},
+	}
+
+	fakeClient := fake.NewFakeClientWithScheme(scheme.Scheme, c, mp)
+	r := &ClusterReconciler{
+		Client: fakeClient,
+		Log:    log.Log,
+	}
+
+	// The control plane MachinePool does not have ready instances yet.
+	g.Expect(r.reconcileControlPlaneInitialized(ctx, c)).To(Succeed())
+	g.Expect(c.Status.ControlPlaneInitialized).To(BeFalse())
+
+	// The control plane MachinePool reports a ready instance.
+	mp.Status.ReadyReplicas = 1
+	g.Expect(fakeClient.Update(ctx, mp)).To(Succeed())
+
+	g.Expect(r.reconcileControlPlaneInitialized(ctx, c)).To(Succeed())
+	g.Expect(c.Status.ControlPlaneInitialized).To(BeTrue())
+}
+
+func TestReconcileControlPlaneInitializedReset(t *testing.T) {
+	tests := []struct {
+		name                         string
+		resetControlPlaneInitialized bool
+		wantInitializedAfterRemoval  bool
+	}{
+		{
+			name:                         "reset disabled, should keep ControlPlaneInitialized",
+			resetControlPlaneInitialized: false,
+			wantInitializedAfterRemoval:  true,
+		},
+		{
+			name:                         "reset enabled, should reset ControlPlaneInitialized",
+			resetControlPlaneInitialized: true,
+			wantInitializedAfterRemoval:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			c := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "c",
+					Namespace: "test",
+				},
+			}
+			m := newMachineBuilder().named("control-plane").inCluster(c).controlPlane().build()
+			m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "control-plane"}
+
+			fakeClient := fake.NewFakeClientWithScheme(scheme.Scheme, c, &m)
+			r := &ClusterReconciler{
+				Client:                       fakeClient,
+				Log:                          log.Log,
+				ResetControlPlaneInitialized: tt.resetControlPlaneInitialized,
+			}
+
+			// The control plane Machine has a NodeRef.
+			g.Expect(r.reconcileControlPlaneInitialized(ctx, c)).To(Succeed())
+			g.Expect(c.Status.ControlPlaneInitialized).To(BeTrue())
+
+			// The last initialized control plane Machine is removed.
+			g.Expect(fakeClient.Delete(ctx, &m)).To(Succeed())
+
+			g.Expect(r.reconcileControlPlaneInitialized(ctx, c)).To(Succeed())
+			g.Expect(c.Status.ControlPlaneInitialized).To(Equal(tt.wantInitializedAfterRemoval))
+		})
+	}
+}
+
+func TestPatchClusterSummaryConditions(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	tests := []struct {
+		name                       string
+		summaryConditions          []clusterv1.ConditionType
+		negativePolarityConditions []clusterv1.ConditionType
+		mustBeTrueConditions       []clusterv1.ConditionType
+		wantReady                  bool
+	}{
+		{
+			name:      "default summary ignores additional conditions",
+			wantReady: true,
+		},
+		{
+			name: "additional summary condition contributes to Ready",
+			summaryConditions: []clusterv1.ConditionType{
+				clusterv1.ControlPlaneReadyCondition,
+				clusterv1.InfrastructureReadyCondition,
+				clusterv1.ControlPlaneReachableCondition,
+			},
+			wantReady: false,
+		},
+		{
+			name: "true negative polarity condition lowers Ready",
+			summaryConditions: []clusterv1.ConditionType{
+				clusterv1.ControlPlaneReadyCondition,
+				clusterv1.InfrastructureReadyCondition,
+				"InfrastructureDegraded",
+			},
+			negativePolarityConditions: []clusterv1.ConditionType{"InfrastructureDegraded"},
+			wantReady:                  false,
+		},
+		{
+			name: "false negative polarity condition does not lower Ready",
+			summaryConditions: []clusterv1.ConditionType{
+				clusterv1.ControlPlaneReadyCondition,
+				clusterv1.InfrastructureReadyCondition,
+				"ControlPlaneDegraded",
+			},
+			negativePolarityConditions: []clusterv1.ConditionType{"ControlPlaneDegraded"},
+			wantReady:                  true,
+		},
+		{
+			name: "unknown condition does not lower Ready",
+			summaryConditions: []clusterv1.ConditionType{
+				clusterv1.ControlPlaneReadyCondition,
+				clusterv1.InfrastructureReadyCondition,
+				"InfrastructureProvisioned",
+			},
+			wantReady: true,
+		},
+		{
+			name: "unknown must be true condition lowers Ready",
+			summaryConditions: []clusterv1.ConditionType{
+				clusterv1.ControlPlaneReadyCondition,
+				clusterv1.InfrastructureReadyCondition,
+				"InfrastructureProvisioned",
+			},
+			mustBeTrueConditions: []clusterv1.ConditionType{"InfrastructureProvisioned"},
+			wantReady:            false,
+		},
+		{
+			name: "missing must be true condition lowers Ready",
+			summaryConditions: []clusterv1.ConditionType{
+				clusterv1.ControlPlaneReadyCondition,
+				clusterv1.InfrastructureReadyCondition,
+				"InfrastructureInstalled",
+			},
+			mustBeTrueConditions: []clusterv1.ConditionType{"InfrastructureInstalled"},
+			wantReady:            false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test",
+				},
+			}
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster)
+
+			patchHelper, err := patch.NewHelper(cluster, c)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			conditions.MarkTrue(cluster, clusterv1.ControlPlaneReadyCondition)
+			conditions.MarkTrue(cluster, clusterv1.InfrastructureReadyCondition)
+			conditions.MarkFalse(cluster, clusterv1.ControlPlaneReachableCondition, clusterv1.ControlPlaneUnreachableReason, clusterv1.ConditionSeverityWarning, "")
+			conditions.MarkTrue(cluster, "InfrastructureDegraded")
+			conditions.MarkFalse(cluster, "ControlPlaneDegraded", "AsExpected", clusterv1.ConditionSeverityInfo, "")
+			conditions.MarkUnknown(cluster, "InfrastructureProvisioned", "Provisioning", "")
+
+			r := &ClusterReconciler{
+				Client:                     c,
+				Log:                        log.Log,
+				SummaryConditions:          tt.summaryConditions,
+				NegativePolarityConditions: tt.negativePolarityConditions,
+				MustBeTrueConditions:       tt.mustBeTrueConditions,
+			}
+			g.Expect(r.patchCluster(ctx, patchHelper, cluster)).To(Succeed())
+			g.Expect(conditions.IsTrue(cluster, clusterv1.ReadyCondition)).To(Equal(tt.wantReady))
+		})
+	}
+}
+
+func TestDescendantDeleteOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantGracePeriod *int64
+		wantErr         bool
+	}{
+		{
+			name: "annotation not set, should use the default grace period",
+		},
+		{
+			name:            "annotation set, should use the configured grace period",
+			annotations:     map[string]string{clusterv1.DeleteGraceSecondsAnnotation: "120"},
+			wantGracePeriod: pointer.Int64Ptr(120),
+		},
+		{
+			name:            "annotation set to zero, should delete immediately",
+			annotations:     map[string]string{clusterv1.DeleteGraceSecondsAnnotation: "0"},
+			wantGracePeriod: pointer.Int64Ptr(0),
+		},
+		{
+			name:        "annotation not an integer, should return error",
+			annotations: map[string]string{clusterv1.DeleteGraceSecondsAnnotation: "foo"},
+			wantErr:     true,
+		},
+		{
+			name:        "annotation negative, should return error",
+			annotations: map[string]string{clusterv1.DeleteGraceSecondsAnnotation: "-10"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster",
+					Namespace:   "test",
+					Annotations: tt.annotations,
+				},
+			}
+
+			opts, err := descendantDeleteOptions(cluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			deleteOpts := &client.DeleteOptions{}
+			deleteOpts.ApplyOptions(opts)
+			g.Expect(deleteOpts.GracePeriodSeconds).To(Equal(tt.wantGracePeriod))
+		})
+	}
+}
+
+func TestRecordReconcileOutcome(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+
+	recordReconcileOutcome(cluster, errors.New("failed to reconcile"), time.Now())
+	g.Expect(cluster.Status.LastReconcileTime).NotTo(BeNil())
+	g.Expect(cluster.Status.ConsecutiveReconcileErrors).To(Equal(int32(1)))
+
+	recordReconcileOutcome(cluster, errors.New("failed to reconcile"), time.Now())
+	g.Expect(cluster.Status.ConsecutiveReconcileErrors).To(Equal(int32(2)))
+
+	recordReconcileOutcome(cluster, nil, time.Now())
+	g.Expect(cluster.Status.LastReconcileTime).NotTo(BeNil())
+	g.Expect(cluster.Status.ConsecutiveReconcileErrors).To(Equal(int32(0)))
+}
+
+func TestClusterReconciler_reconcileDeleteExternalRefs(t *testing.T) {
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+		Kind:       "GenericControlPlane",
+		Name:       "test-control-plane",
+		Namespace:  "test",
+	}
+	infrastructureRef := &corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+		Kind:       "GenericInfrastructureCluster",
+		Name:       "test-infrastructure",
+		Namespace:  "test",
+	}
+	newObj := func(ref *corev1.ObjectReference) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+		obj.SetName(ref.Name)
+		obj.SetNamespace(ref.Namespace)
+		return obj
+	}
+	notFound := apierrors.NewNotFound(schema.GroupResource{}, "")
+	noMatch := &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "controlplane.cluster.x-k8s.io", Kind: "GenericControlPlane"}}
+
+	tests := []struct {
+		name              string
+		ref               *corev1.ObjectReference
+		getErr            error
+		wantErr           bool
+		wantFinalizer     bool
+		wantObjectDeleted bool
+	}{
+		{
+			name:          "control plane not found, should remove the finalizer",
+			ref:           controlPlaneRef,
+			getErr:        notFound,
+			wantFinalizer: false,
+		},
+		{
+			name:          "control plane kind not installed, should return error",
+			ref:           controlPlaneRef,
+			getErr:        noMatch,
+			wantErr:       true,
+			wantFinalizer: true,
+		},
+		{
+			name:          "control plane get error, should return error",
+			ref:           controlPlaneRef,
+			getErr:        errors.New("connection refused"),
+			wantErr:       true,
+			wantFinalizer: true,
+		},
+		{
+			name:              "control plane found, should delete it and keep the finalizer",
+			ref:               controlPlaneRef,
+			wantFinalizer:     true,
+			wantObjectDeleted: true,
+		},
+		{
+			name:          "infrastructure not found, should remove the finalizer",
+			ref:           infrastructureRef,
+			getErr:        notFound,
+			wantFinalizer: false,
+		},
+		{
+			name:          "infrastructure get error, should return error",
+			ref:           infrastructureRef,
+			getErr:        errors.New("connection refused"),
+			wantErr:       true,
+			wantFinalizer: true,
+		},
+		{
+			name:              "infrastructure found, should delete it and keep the finalizer",
+			ref:               infrastructureRef,
+			wantFinalizer:     true,
+			wantObjectDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cluster",
+					Namespace:  "test",
+					Finalizers: []string{clusterv1.ClusterFinalizer},
+				},
+			}
+			if tt.ref == controlPlaneRef {
+				cluster.Spec.ControlPlaneRef = tt.ref
+			} else {
+				cluster.Spec.InfrastructureRef = tt.ref
+			}
+
+			obj := newObj(tt.ref)
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, cluster, obj)
+
+			recorder := record.NewFakeRecorder(32)
+			r := &ClusterReconciler{
+				Client: c,
+				Log:    log.Log,
+				ExternalGetter: ExternalGetterFunc(func(_ context.Context, _ client.Client, ref *corev1.ObjectReference, _ string) (*unstructured.Unstructured, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return newObj(ref), nil
+				}),
+				recorder: recorder,
+			}
+
+			_, err := r.reconcileDelete(ctx, cluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(capierrors.IsDeletionBlocked(err)).To(BeTrue())
+				blockedErr := errors.Cause(err).(*capierrors.DeletionBlockedError)
+				g.Expect(blockedErr.Kind).To(Equal(tt.ref.Kind))
+				g.Expect(blockedErr.Names).To(ConsistOf(tt.ref.Name))
+				g.Expect(blockedErr.Err).To(Equal(tt.getErr))
+				g.Expect(errors.Is(err, tt.getErr)).To(BeTrue())
+				g.Expect(recorder.Events).To(Receive(ContainSubstring("DeletionBlocked")))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tt.wantFinalizer {
+				g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+			} else {
+				g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+			}
+
+			err = c.Get(ctx, util.ObjectKey(obj), newObj(tt.ref))
+			g.Expect(apierrors.IsNotFound(err)).To(Equal(tt.wantObjectDeleted))
+		})
+	}
+}
+
+func TestClusterReconciler_crdToClusters(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	newCluster := func(name string, infrastructureKind string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+					Kind:       infrastructureKind,
+					Name:       name,
+				},
+			},
+		}
+	}
+	matching := newCluster("matching", "InfrastructureMachine")
+	other := newCluster("other", "OtherInfrastructureMachine")
+
+	r := &ClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, matching, other),
+		Log:    log.Log,
+	}
+
+	crd := external.TestGenericInfrastructureCRD.DeepCopy()
+
+	// The CRD is not yet established, no Clusters should be enqueued.
+	g.Expect(r.crdToClusters(handler.MapObject{Meta: crd, Object: crd})).To(BeEmpty())
+
+	// Once the CRD is established, only the Clusters referencing its kind should be enqueued.
+	crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+		{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+	}
+	g.Expect(r.crdToClusters(handler.MapObject{Meta: crd, Object: crd})).To(Equal([]ctrl.Request{
+		{NamespacedName: util.ObjectKey(matching)},
+	}))
+}
+
+func TestClusterReconciler_kubeconfigSecretToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &ClusterReconciler{
+		Log: log.Log,
+	}
+
+	newSecret := func(name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    labels,
+			},
+		}
+	}
+
+	// Only the Kubeconfig Secret of a Cluster enqueues the Cluster.
+	kubeconfigSecret := newSecret("test-cluster-kubeconfig", map[string]string{clusterv1.ClusterLabelName: "test-cluster"})
+	g.Expect(r.kubeconfigSecretToCluster(handler.MapObject{Meta: kubeconfigSecret, Object: kubeconfigSecret})).To(Equal([]ctrl.Request{
+		{NamespacedName: client.ObjectKey{Namespace: "test", Name: "test-cluster"}},
+	}))
+
+	caSecret := newSecret("test-cluster-ca", map[string]string{clusterv1.ClusterLabelName: "test-cluster"})
+	g.Expect(r.kubeconfigSecretToCluster(handler.MapObject{Meta: caSecret, Object: caSecret})).To(BeEmpty())
+
+	unlabeledSecret := newSecret("test-cluster-kubeconfig", nil)
+	g.Expect(r.kubeconfigSecretToCluster(handler.MapObject{Meta: unlabeledSecret, Object: unlabeledSecret})).To(BeEmpty())
+
+	// The events for the other Secrets are filtered out before being mapped.
+	g.Expect(kubeconfigSecrets().Update(event.UpdateEvent{MetaOld: kubeconfigSecret, ObjectOld: kubeconfigSecret, MetaNew: kubeconfigSecret, ObjectNew: kubeconfigSecret})).To(BeTrue())
+	g.Expect(kubeconfigSecrets().Create(event.CreateEvent{Meta: caSecret, Object: caSecret})).To(BeFalse())
+	g.Expect(kubeconfigSecrets().Delete(event.DeleteEvent{Meta: unlabeledSecret, Object: unlabeledSecret})).To(BeFalse())
+}
+
+func TestIgnoreClusterStatusUpdates(t *testing.T) {
+	g := NewWithT(t)
+
+	oldCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Generation: 1,
+		},
+	}
+	update := func(newCluster *clusterv1.Cluster) bool {
+		return ignoreClusterStatusUpdates().Update(event.UpdateEvent{
+			MetaOld:   oldCluster,
+			ObjectOld: oldCluster,
+			MetaNew:   newCluster,
+			ObjectNew: newCluster,
+		})
+	}
+
+	// The status written at the end of each reconciliation doesn't trigger another reconciliation.
+	newCluster := oldCluster.DeepCopy()
+	lastReconcileTime := metav1.Now()
+	newCluster.Status.LastReconcileTime = &lastReconcileTime
+	g.Expect(update(newCluster)).To(BeFalse())
+
+	// Changes to the spec or to the annotations do.
+	newCluster = oldCluster.DeepCopy()
+	newCluster.Generation = 2
+	g.Expect(update(newCluster)).To(BeTrue())
+
+	newCluster = oldCluster.DeepCopy()
+	newCluster.Annotations = map[string]string{clusterv1.ForceReconcileAnnotation: ""}
+	g.Expect(update(newCluster)).To(BeTrue())
+
+	// Other kinds are not filtered.
+	machine := &clusterv1.Machine{}
+	g.Expect(ignoreClusterStatusUpdates().Update(event.UpdateEvent{MetaOld: machine, ObjectOld: machine, MetaNew: machine, ObjectNew: machine})).To(BeTrue())
+}
+
+func TestApplyReconcileInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		result      ctrl.Result
+		want        ctrl.Result
+		wantErr     bool
+	}{
+		{
+			name:   "annotation not set, should not change the result",
+			result: ctrl.Result{},
+			want:   ctrl.Result{},
+		},
+		{
+			name:        "annotation set, should requeue after the interval",
+			annotations: map[string]string{clusterv1.ReconcileIntervalAnnotation: "5m"},
+			result:      ctrl.Result{},
+			want:        ctrl.Result{RequeueAfter: 5 * time.Minute},
+		},
+		{
+			name:        "annotation set, should not delay an earlier requeue",
+			annotations: map[string]string{clusterv1.ReconcileIntervalAnnotation: "5m"},
+			result:      ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second},
+			want:        ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second},
+		},
+		{
+			name:        "annotation set to an invalid duration, should return error",
+			annotations: map[string]string{clusterv1.ReconcileIntervalAnnotation: "five minutes"},
+			result:      ctrl.Result{},
+			want:        ctrl.Result{},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster",
+					Namespace:   "test",
+					Annotations: tt.annotations,
+				},
+			}
+
+			got, err := applyReconcileInterval(cluster, tt.result)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+// lateDescendantClient is a client creating a descendant Machine right after the first list of Machines,
+// so to simulate a descendant created while the Cluster is being deleted.
+type lateDescendantClient struct {
+	client.Client
+	machine *clusterv1.Machine
+	created bool
+}
+
+func (c *lateDescendantClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if _, ok := list.(*clusterv1.MachineList); ok && !c.created {
+		c.created = true
+		return c.Client.Create(ctx, c.machine)
+	}
+	return nil
+}
+
+func TestClusterReconciler_reconcileDeleteLateDescendant(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	lateMachine := newMachineBuilder().named("late").inCluster(cluster).build()
+
+	c := &lateDescendantClient{
+		Client:  fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		machine: &lateMachine,
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The first reconcile should detect the late descendant and requeue without removing the finalizer.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+	g.Expect(c.created).To(BeTrue())
+}
+
+// deleteRecordingClient is a client recording the names of the objects deleted individually,
+// and the number of DeleteAllOf calls.
+type deleteRecordingClient struct {
+	client.Client
+	deleted          []string
+	deleteAllOfCalls int
+}
+
+func (c *deleteRecordingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	c.deleted = append(c.deleted, accessor.GetName())
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *deleteRecordingClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	c.deleteAllOfCalls++
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// writeRecordingClient is a client recording the verbs of all the writes.
+type writeRecordingClient struct {
+	client.Client
+	writes []string
+}
+
+func (c *writeRecordingClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	c.writes = append(c.writes, "create")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *writeRecordingClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	c.writes = append(c.writes, "update")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *writeRecordingClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.writes = append(c.writes, "patch")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *writeRecordingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	c.writes = append(c.writes, "delete")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *writeRecordingClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	c.writes = append(c.writes, "deleteAllOf")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *writeRecordingClient) Status() client.StatusWriter {
+	c.writes = append(c.writes, "status")
+	return c.Client.Status()
+}
+
+func TestClusterReconciler_ReconcileReadOnly(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).build()
+
+	c := &writeRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &md),
+	}
+	r := &ClusterReconciler{
+		Client:   readOnlyClient{Client: c, log: log.Log},
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+		ReadOnly: true,
+	}
+
+	// No write is issued, neither to the Cluster nor to its descendants.
+	_, err := r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.writes).To(BeEmpty())
+
+	got := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(got.Finalizers).To(BeEmpty())
+	g.Expect(got.Status.Phase).To(BeEmpty())
+
+	// The Cluster status is still computed in memory.
+	_, _ = r.reconcile(ctx, cluster)
+	r.reconcilePhase(ctx, cluster)
+	g.Expect(c.writes).To(BeEmpty())
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+	g.Expect(cluster.Status.Phase).NotTo(BeEmpty())
+}
+
+func TestClusterReconciler_ReconcileUnmanaged(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+			Annotations: map[string]string{
+				clusterv1.UnmanagedAnnotation: "",
+			},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).build()
+
+	c := &writeRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &md),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// Neither the Cluster nor its descendants are mutated.
+	res, err := r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{}))
+	g.Expect(c.writes).To(BeEmpty())
+
+	got := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(got.Finalizers).To(BeEmpty())
+	g.Expect(got.Status.LastReconcileTime).To(BeNil())
+
+	// A finalizer left over from when the Cluster was managed is removed.
+	got.Finalizers = []string{clusterv1.ClusterFinalizer}
+	g.Expect(c.Client.Update(ctx, got)).To(Succeed())
+	_, err = r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	got = &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(got.Finalizers).To(BeEmpty())
+	g.Expect(got.Status.LastReconcileTime).To(BeNil())
+}
+
+func TestClusterReconciler_reconcileDeleteWorkerMachinesInBulk(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	worker1 := newMachineBuilder().named("worker1").inCluster(cluster).ownedBy(cluster).build()
+	worker2 := newMachineBuilder().named("worker2").inCluster(cluster).ownedBy(cluster).build()
+	controlPlane := newMachineBuilder().named("control-plane").inCluster(cluster).ownedBy(cluster).controlPlane().build()
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &worker1, &worker2, &controlPlane),
+	}
+	r := &ClusterReconciler{
+		Client:                     c,
+		Log:                        log.Log,
+		DeleteWorkerMachinesInBulk: true,
+		recorder:                   record.NewFakeRecorder(32),
+	}
+
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+
+	// Worker Machines are deleted at once, while the control plane Machine is deleted individually.
+	g.Expect(c.deleteAllOfCalls).To(Equal(1))
+	g.Expect(c.deleted).To(ConsistOf("control-plane"))
+	g.Expect(ensureNoDescendants(ctx, r, cluster)).To(Succeed())
+}
+
+func TestEnsureNoDescendants(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+	machine := newMachineBuilder().named("lingering").inCluster(cluster).build()
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &machine)
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// A lingering descendant is detected.
+	lingeringCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	err := ensureNoDescendants(lingeringCtx, r, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("lingering"))
+
+	// Once the descendant is gone, no error is returned.
+	g.Expect(c.Delete(ctx, &machine)).To(Succeed())
+	g.Expect(ensureNoDescendants(ctx, r, cluster)).To(Succeed())
+}
+
+func TestClusterReconciler_reconcileDeleteWorkerMachinesInBulkOwnedMachines(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	ms := &clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: "ms"}}
+	worker1 := newMachineBuilder().named("worker1").inCluster(cluster).ownedBy(cluster).build()
+	worker2 := newMachineBuilder().named("worker2").inCluster(cluster).ownedByMachineSet(ms).build()
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &worker1, &worker2),
+	}
+	r := &ClusterReconciler{
+		Client:                     c,
+		Log:                        log.Log,
+		DeleteWorkerMachinesInBulk: true,
+		recorder:                   record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The Machine owned by the MachineSet prevents the bulk deletion, so only the Machine owned by the Cluster is deleted.
+	g.Expect(c.deleteAllOfCalls).To(BeZero())
+	g.Expect(c.deleted).To(ConsistOf("worker1"))
+
+	machines := &clusterv1.MachineList{}
+	g.Expect(c.List(ctx, machines, client.InNamespace(cluster.Namespace))).To(Succeed())
+	g.Expect(machines.Items).To(HaveLen(1))
+	g.Expect(machines.Items[0].Name).To(Equal("worker2"))
+}
+
+func TestClusterReconciler_reconcileDeleteOrderConfigMap(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+			Annotations: map[string]string{
+				clusterv1.DeletionOrderConfigMapAnnotation: "deletion-order",
+			},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deletion-order",
+			Namespace: "test",
+		},
+		Data: map[string]string{
+			"order": "Machine, MachineSet\nMachineDeployment",
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).ownedBy(cluster).build()
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).ownedBy(cluster).build()
+	worker := newMachineBuilder().named("worker").inCluster(cluster).ownedBy(cluster).build()
+	controlPlane := newMachineBuilder().named("control-plane").inCluster(cluster).ownedBy(cluster).controlPlane().build()
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, configMap, &md, &ms, &worker, &controlPlane),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The descendants are deleted in the requested order, with the control plane Machines last.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.deleted).To(Equal([]string{"worker", "ms", "md", "control-plane"}))
+}
+
+func TestClusterReconciler_reconcileDeleteDescendantChurn(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			UID:        "cluster-uid",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	newMachineDeployment := func(i int) *clusterv1.MachineDeployment {
+		md := newMachineDeploymentBuilder().named("md").inCluster(cluster).ownedBy(cluster).build()
+		md.UID = types.UID(fmt.Sprintf("md-uid-%d", i))
+		return &md
+	}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, newMachineDeployment(0))
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client:                   c,
+		Log:                      log.Log,
+		recorder:                 recorder,
+		DescendantChurnThreshold: 2,
+	}
+
+	// The MachineDeployment is recreated as soon as it is deleted, e.g. by a misbehaving controller.
+	for i := 1; i <= 2; i++ {
+		_, err := r.reconcileDelete(ctx, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(conditions.Has(cluster, clusterv1.DescendantsStableCondition)).To(BeFalse())
+		g.Expect(c.Create(ctx, newMachineDeployment(i))).To(Succeed())
+	}
+
+	// Deleting the same instance again does not count as churn.
+	md := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: "md"}, md)).To(Succeed())
+	g.Expect(r.descendantDeletions.record(cluster.UID, "MachineDeployment/md", md.UID)).To(Equal(3))
+	g.Expect(r.descendantDeletions.record(cluster.UID, "MachineDeployment/md", md.UID)).To(Equal(3))
+
+	// Once the threshold is exceeded, the churning MachineDeployment is reported.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.DescendantsStableCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.DescendantsStableCondition)).To(Equal(clusterv1.DescendantChurningReason))
+	g.Expect(conditions.GetMessage(cluster, clusterv1.DescendantsStableCondition)).To(ContainSubstring(`"md" has been deleted 3 times`))
+
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	g.Expect(events).To(ContainElement(ContainSubstring("DescendantChurning")))
+
+	// The recorded deletions are dropped once the Cluster is deleted.
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).To(BeEmpty())
+	g.Expect(r.descendantDeletions.counts).NotTo(HaveKey(cluster.UID))
+}
+
+func TestClusterReconciler_reconcileDeleteOrderConfigMapNotFound(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+			Annotations: map[string]string{
+				clusterv1.DeletionOrderConfigMapAnnotation: "deletion-order",
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The deletion does not proceed without the requested order.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+func TestClusterReconciler_reconcileDeleteEmptyMachineSets(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md1").inCluster(cluster).ownedBy(cluster).build()
+	mdOwnerRef := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "MachineDeployment",
+		Name:       md.Name,
+	}
+
+	emptyMS := newMachineSetBuilder().named("ms-empty").inCluster(cluster).build()
+	emptyMS.OwnerReferences = []metav1.OwnerReference{mdOwnerRef}
+	emptyMS.Spec.Replicas = pointer.Int32Ptr(0)
+
+	scaledDownMS := newMachineSetBuilder().named("ms-scaled-down").inCluster(cluster).build()
+	scaledDownMS.OwnerReferences = []metav1.OwnerReference{mdOwnerRef}
+	scaledDownMS.Spec.Replicas = pointer.Int32Ptr(0)
+	scaledDownMachine := newMachineBuilder().named("ms-scaled-down-machine").inCluster(cluster).ownedByMachineSet(&scaledDownMS).build()
+
+	scaledUpMS := newMachineSetBuilder().named("ms-scaled-up").inCluster(cluster).build()
+	scaledUpMS.OwnerReferences = []metav1.OwnerReference{mdOwnerRef}
+	scaledUpMS.Spec.Replicas = pointer.Int32Ptr(1)
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &md, &emptyMS, &scaledDownMS, &scaledDownMachine, &scaledUpMS),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+
+	// The empty MachineSet is deleted in the first pass, together with the direct descendants of the Cluster,
+	// while MachineSets with replicas or Machines are left to their MachineDeployment.
+	g.Expect(c.deleted).To(ConsistOf("md1", "ms-empty"))
+
+	machineSets := &clusterv1.MachineSetList{}
+	g.Expect(c.List(ctx, machineSets, client.InNamespace(cluster.Namespace))).To(Succeed())
+	g.Expect(machineSets.Items).To(HaveLen(2))
+}
+
+func TestClusterReconciler_reconcileDeleteParallelMachineDeletion(t *testing.T) {
+	tests := []struct {
+		name                    string
+		parallelMachineDeletion bool
+		wantDeleted             []string
+	}{
+		{
+			name:        "control plane Machines are deleted last by default",
+			wantDeleted: []string{"worker1", "worker2", "control-plane1", "control-plane2"},
+		},
+		{
+			name:                    "control plane and worker Machines are deleted interleaved with ParallelMachineDeletion",
+			parallelMachineDeletion: true,
+			wantDeleted:             []string{"control-plane1", "worker1", "control-plane2", "worker2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cluster",
+					Namespace:  "test",
+					Finalizers: []string{clusterv1.ClusterFinalizer},
+				},
+			}
+			worker1 := newMachineBuilder().named("worker1").inCluster(cluster).ownedBy(cluster).build()
+			worker2 := newMachineBuilder().named("worker2").inCluster(cluster).ownedBy(cluster).build()
+			controlPlane1 := newMachineBuilder().named("control-plane1").inCluster(cluster).ownedBy(cluster).controlPlane().build()
+			controlPlane2 := newMachineBuilder().named("control-plane2").inCluster(cluster).ownedBy(cluster).controlPlane().build()
+
+			c := &deleteRecordingClient{
+				Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &worker1, &worker2, &controlPlane1, &controlPlane2),
+			}
+			r := &ClusterReconciler{
+				Client:                  c,
+				Log:                     log.Log,
+				ParallelMachineDeletion: tt.parallelMachineDeletion,
+				recorder:                record.NewFakeRecorder(32),
+			}
+
+			_, err := r.reconcileDelete(ctx, cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(c.deleted).To(Equal(tt.wantDeleted))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileDeleteMinDescendantAge(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	creationTimestamp := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	machine := newMachineBuilder().named("machine").inCluster(cluster).ownedBy(cluster).build()
+	machine.CreationTimestamp = creationTimestamp
+
+	clock := &fakeClock{now: creationTimestamp.Add(time.Second)}
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &machine),
+	}
+	r := &ClusterReconciler{
+		Client:                         c,
+		Log:                            log.Log,
+		MinDescendantAgeBeforeDeletion: 3 * time.Second,
+		Clock:                          clock,
+		recorder:                       record.NewFakeRecorder(32),
+	}
+
+	// The Machine has just been created, so its deletion is deferred until it is old enough.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(2 * time.Second))
+	g.Expect(c.deleted).To(BeEmpty())
+
+	// On the next reconcile, the Machine is old enough to be deleted.
+	clock.now = creationTimestamp.Add(3 * time.Second)
+	res, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(c.deleted).To(ConsistOf("machine"))
+}
+
+// slowMachineSetListClient is a client whose List calls for MachineSets block until the context is done.
+type slowMachineSetListClient struct {
+	client.Client
+}
+
+func (c *slowMachineSetListClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if _, ok := list.(*clusterv1.MachineSetList); ok {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestClusterReconciler_reconcileDeleteListTimeout(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client: &slowMachineSetListClient{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		},
+		Log:                    log.Log,
+		recorder:               recorder,
+		ListDescendantsTimeout: 10 * time.Millisecond,
+	}
+
+	// A slow List should requeue without returning an error, and without removing the finalizer.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(listDescendantsTimeoutRequeueAfter))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("MachineSets"))
+}
+
+func TestClusterReconciler_identicalReferences(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+		Kind:       "GenericInfrastructureCluster",
+		Name:       "test",
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef:   ref.DeepCopy(),
+			InfrastructureRef: ref.DeepCopy(),
+		},
+	}
+
+	gets := 0
+	r := &ClusterReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Log:    log.Log,
+		ExternalGetter: ExternalGetterFunc(func(_ context.Context, _ client.Client, _ *corev1.ObjectReference, _ string) (*unstructured.Unstructured, error) {
+			gets++
+			return nil, apierrors.NewNotFound(schema.GroupResource{}, "")
+		}),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// Identical references are surfaced in a condition.
+	g.Expect(r.reconcileReferences(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.DistinctReferencesCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.DistinctReferencesCondition)).To(Equal(clusterv1.IdenticalReferencesReason))
+
+	// The referenced object is handled only once during deletion.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gets).To(Equal(1))
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Distinct references do not raise the condition.
+	cluster.Spec.InfrastructureRef.Name = "other"
+	g.Expect(r.reconcileReferences(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.Has(cluster, clusterv1.DistinctReferencesCondition)).To(BeFalse())
+}
+
+func TestClusterReconciler_reconcileDeleteClusterSecrets(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	newSecret := func(name string, owned bool) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: cluster.Name,
+				},
+			},
+		}
+		if owned {
+			secret.OwnerReferences = []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+				},
+			}
+		}
+		return secret
+	}
+	ownedSecret := newSecret("test-cluster-kubeconfig", true)
+	unownedSecret := newSecret("user-secret", false)
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, ownedSecret, unownedSecret)
+	r := &ClusterReconciler{
+		Client:               c,
+		Log:                  log.Log,
+		DeleteClusterSecrets: true,
+		recorder:             record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Only the Secret owned by the Cluster is deleted.
+	err = c.Get(ctx, util.ObjectKey(ownedSecret), &corev1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(c.Get(ctx, util.ObjectKey(unownedSecret), &corev1.Secret{})).To(Succeed())
+}
+
+func TestClusterReconciler_reconcileDeleteSecretsInOtherNamespaces(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			UID:        "uid",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	newSecret := func(name, namespace string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+		}
+	}
+	clusterSecret := newSecret("test-cluster-kubeconfig", "secondary", map[string]string{
+		clusterv1.ClusterLabelName: cluster.Name,
+	})
+	otherClusterSecret := newSecret("other-kubeconfig", "secondary", map[string]string{
+		clusterv1.ClusterLabelName:    cluster.Name,
+		clusterv1.ClusterUIDLabelName: "other-uid",
+	})
+	unlabeledSecret := newSecret("user-secret", "secondary", nil)
+	notConfiguredSecret := newSecret("test-cluster-kubeconfig", "other", map[string]string{
+		clusterv1.ClusterLabelName: cluster.Name,
+	})
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, cluster, clusterSecret, otherClusterSecret, unlabeledSecret, notConfiguredSecret)
+	r := &ClusterReconciler{
+		Client:           c,
+		Log:              log.Log,
+		SecretNamespaces: []string{"secondary"},
+		recorder:         record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Only the Secret labeled for the Cluster in the configured namespace is deleted.
+	err = c.Get(ctx, util.ObjectKey(clusterSecret), &corev1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(c.Get(ctx, util.ObjectKey(otherClusterSecret), &corev1.Secret{})).To(Succeed())
+	g.Expect(c.Get(ctx, util.ObjectKey(unlabeledSecret), &corev1.Secret{})).To(Succeed())
+	g.Expect(c.Get(ctx, util.ObjectKey(notConfiguredSecret), &corev1.Secret{})).To(Succeed())
+}
+
+func TestClusterReconciler_finalizerMetrics(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	counterValue := func(name string) float64 {
+		mr, err := metrics.Registry.Gather()
+		g.Expect(err).NotTo(HaveOccurred())
+		mf := getMetricFamily(mr, name)
+		if mf == nil {
+			return 0
+		}
+		return mf.GetMetric()[0].GetCounter().GetValue()
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+	r := &ClusterReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	added := counterValue("capi_cluster_finalizer_added_total")
+	removed := counterValue("capi_cluster_finalizer_removed_total")
+
+	// Adding the finalizer increments the added counter only once.
+	_, _ = r.reconcile(ctx, cluster)
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+	g.Expect(counterValue("capi_cluster_finalizer_added_total")).To(Equal(added + 1))
+	_, _ = r.reconcile(ctx, cluster)
+	g.Expect(counterValue("capi_cluster_finalizer_added_total")).To(Equal(added + 1))
+
+	// Removing the finalizer increments the removed counter.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+	g.Expect(counterValue("capi_cluster_finalizer_removed_total")).To(Equal(removed + 1))
+}
+
+func TestClusterReconciler_reconcileDeleteControlPlaneDeleting(t *testing.T) {
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+		Kind:       "GenericControlPlane",
+		Name:       "test-control-plane",
+		Namespace:  "test",
+	}
+
+	tests := []struct {
+		name             string
+		deletingSince    time.Duration
+		wantRequeue      bool
+		wantConditionSet bool
+	}{
+		{
+			name:          "control plane just entering deletion, should requeue at the end of the grace period",
+			deletingSince: 0,
+			wantRequeue:   true,
+		},
+		{
+			name:             "control plane in deletion for longer than the grace period, should report it as deleting",
+			deletingSince:    time.Minute,
+			wantConditionSet: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cluster",
+					Namespace:  "test",
+					Finalizers: []string{clusterv1.ClusterFinalizer},
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: controlPlaneRef,
+				},
+			}
+
+			newObj := func() *unstructured.Unstructured {
+				obj := &unstructured.Unstructured{}
+				obj.SetAPIVersion(controlPlaneRef.APIVersion)
+				obj.SetKind(controlPlaneRef.Kind)
+				obj.SetName(controlPlaneRef.Name)
+				obj.SetNamespace(controlPlaneRef.Namespace)
+				return obj
+			}
+
+			r := &ClusterReconciler{
+				Client:                          fake.NewFakeClientWithScheme(scheme.Scheme, cluster, newObj()),
+				Log:                             log.Log,
+				ControlPlaneDeletingGracePeriod: 2 * time.Second,
+				ExternalGetter: ExternalGetterFunc(func(_ context.Context, _ client.Client, _ *corev1.ObjectReference, _ string) (*unstructured.Unstructured, error) {
+					obj := newObj()
+					deletionTimestamp := metav1.NewTime(time.Now().Add(-tt.deletingSince))
+					obj.SetDeletionTimestamp(&deletionTimestamp)
+					return obj, nil
+				}),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			res, err := r.reconcileDelete(ctx, cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+			if tt.wantRequeue {
+				g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+				g.Expect(res.RequeueAfter).To(BeNumerically("<=", r.ControlPlaneDeletingGracePeriod))
+			} else {
+				g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+			}
+			if tt.wantConditionSet {
+				g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneReadyCondition)).To(Equal(clusterv1.ControlPlaneDeletingReason))
+			} else {
+				g.Expect(conditions.Has(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeFalse())
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileDeleteStandaloneMachineSet(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).ownedBy(cluster).build()
+	msMachine1 := newMachineBuilder().named("ms-machine1").inCluster(cluster).ownedByMachineSet(&ms).build()
+	msMachine2 := newMachineBuilder().named("ms-machine2").inCluster(cluster).ownedByMachineSet(&ms).build()
+	standaloneMachine := newMachineBuilder().named("standalone").inCluster(cluster).ownedBy(cluster).build()
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &ms, &msMachine1, &msMachine2, &standaloneMachine),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+
+	// The MachineSet is deleted before the Machines owned by the Cluster, while the Machines owned by
+	// the MachineSet are left to be deleted through it.
+	g.Expect(c.deleted).To(Equal([]string{"ms", "standalone"}))
+
+	machines := &clusterv1.MachineList{}
+	g.Expect(c.List(ctx, machines, client.InNamespace(cluster.Namespace))).To(Succeed())
+	g.Expect(machines.Items).To(HaveLen(2))
+
+	// Once the Machines owned by the MachineSet are gone, the finalizer is removed.
+	g.Expect(c.Delete(ctx, &msMachine1)).To(Succeed())
+	g.Expect(c.Delete(ctx, &msMachine2)).To(Succeed())
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestClusterReconciler_reconcileDeleteControlPlaneAfterWorkers(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+		Kind:       "GenericControlPlane",
+		Name:       "test-control-plane",
+		Namespace:  "test",
+	}
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: controlPlaneRef,
+		},
+	}
+	controlPlane := &unstructured.Unstructured{}
+	controlPlane.SetAPIVersion(controlPlaneRef.APIVersion)
+	controlPlane.SetKind(controlPlaneRef.Kind)
+	controlPlane.SetName(controlPlaneRef.Name)
+	controlPlane.SetNamespace(controlPlaneRef.Namespace)
+
+	// The worker Machine is owned by a MachineSet, so it is not deleted directly by the Cluster, while the control plane
+	// Machine is managed by the control plane provider, which deletes it only once the control plane object is deleted.
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).build()
+	worker := newMachineBuilder().named("worker").inCluster(cluster).ownedByMachineSet(&ms).build()
+	controlPlaneMachine := newMachineBuilder().named("control-plane").inCluster(cluster).controlPlane().build()
+	controlPlaneMachine.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: controlPlaneRef.APIVersion,
+		Kind:       controlPlaneRef.Kind,
+		Name:       controlPlaneRef.Name,
+	}}
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, controlPlane, &ms, &worker, &controlPlaneMachine),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// While the worker Machine exists, the control plane object is not deleted.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(c.deleted).NotTo(ContainElement(controlPlaneRef.Name))
+
+	// Once the worker Machine is gone, the control plane object is deleted, even if its Machine is still there.
+	g.Expect(c.Client.Delete(ctx, &worker)).To(Succeed())
+	g.Expect(c.Client.Delete(ctx, &ms)).To(Succeed())
+	res, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(c.deleted).To(ConsistOf(controlPlaneRef.Name))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+func TestClusterReconciler_reconcileDeletePausedInfrastructure(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	infraRef := &corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+		Kind:       "InfrastructureCluster",
+		Name:       "test-infra",
+		Namespace:  "test",
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: infraRef,
+		},
+	}
+	infra := &unstructured.Unstructured{}
+	infra.SetAPIVersion(infraRef.APIVersion)
+	infra.SetKind(infraRef.Kind)
+	infra.SetName(infraRef.Name)
+	infra.SetNamespace(infraRef.Namespace)
+	infra.SetAnnotations(map[string]string{clusterv1.PausedAnnotation: ""})
+
+	c := &deleteRecordingClient{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, cluster, infra),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// While the infrastructure object is paused, it is not deleted.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(c.deleted).NotTo(ContainElement(infraRef.Name))
+	g.Expect(conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.InfrastructureDeletionPausedReason))
+
+	// Once unpaused, the infrastructure object is deleted.
+	g.Expect(c.Client.Get(ctx, util.ObjectKey(infra), infra)).To(Succeed())
+	infra.SetAnnotations(nil)
+	g.Expect(c.Client.Update(ctx, infra)).To(Succeed())
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.deleted).To(ContainElement(infraRef.Name))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+func TestClusterReconciler_reconcileDeleteControlPlaneDeletingWithClock(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1alpha3",
+		Kind:       "GenericControlPlane",
+		Name:       "test-control-plane",
+		Namespace:  "test",
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: controlPlaneRef,
+		},
+	}
+
+	deletionTimestamp := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	newObj := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(controlPlaneRef.APIVersion)
+		obj.SetKind(controlPlaneRef.Kind)
+		obj.SetName(controlPlaneRef.Name)
+		obj.SetNamespace(controlPlaneRef.Namespace)
+		return obj
+	}
+
+	clock := &fakeClock{now: deletionTimestamp.Add(time.Second)}
+	r := &ClusterReconciler{
+		Client:                          fake.NewFakeClientWithScheme(scheme.Scheme, cluster, newObj()),
+		Log:                             log.Log,
+		ControlPlaneDeletingGracePeriod: time.Minute,
+		Clock:                           clock,
+		ExternalGetter: ExternalGetterFunc(func(_ context.Context, _ client.Client, _ *corev1.ObjectReference, _ string) (*unstructured.Unstructured, error) {
+			obj := newObj()
+			obj.SetDeletionTimestamp(&deletionTimestamp)
+			return obj, nil
+		}),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// Within the grace period, the control plane is not reported as deleting yet.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(conditions.Has(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeFalse())
+
+	// Once the clock moves past the grace period, the control plane is reported as deleting.
+	clock.now = deletionTimestamp.Add(2 * time.Minute)
+	res, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneReadyCondition)).To(Equal(clusterv1.ControlPlaneDeletingReason))
+}
+
+func TestClusterReconciler_ReconcileFrozen(t *testing.T) {
+	tests := []struct {
+		name          string
+		freezeUntil   time.Time
+		wantFrozen    bool
+		wantFinalizer bool
+	}{
+		{
+			name:        "future freeze timestamp, should not mutate the Cluster and requeue at expiry",
+			freezeUntil: time.Now().Add(time.Hour),
+			wantFrozen:  true,
+		},
+		{
+			name:          "past freeze timestamp, should reconcile the Cluster",
+			freezeUntil:   time.Now().Add(-time.Hour),
+			wantFinalizer: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test",
+					Annotations: map[string]string{
+						clusterv1.ReconcileFreezeUntilAnnotation: tt.freezeUntil.Format(time.RFC3339),
+					},
+				},
+			}
+
+			c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster)
+			r := &ClusterReconciler{
+				Client:   c,
+				Log:      log.Log,
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			res, err := r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+			if tt.wantFrozen {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			}
+
+			got := &clusterv1.Cluster{}
+			g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+			if tt.wantFinalizer {
+				g.Expect(got.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+			} else {
+				g.Expect(got.Finalizers).To(BeEmpty())
+				g.Expect(got.Status.LastReconcileTime).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_ReconcileForced(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+			Annotations: map[string]string{
+				clusterv1.ReconcileFreezeUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339),
+				clusterv1.ForceReconcileAnnotation:       "",
+			},
+		},
+	}
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster)
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The Cluster is reconciled even if frozen, and the annotation is removed.
+	_, err := r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	got := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(got.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+	g.Expect(got.Status.LastReconcileTime).NotTo(BeNil())
+	g.Expect(got.Annotations).NotTo(HaveKey(clusterv1.ForceReconcileAnnotation))
+	g.Expect(got.Annotations).To(HaveKey(clusterv1.ReconcileFreezeUntilAnnotation))
+
+	// Without the annotation, the next reconciliation honors the freeze again.
+	res, err := r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+}
+
+func TestClusterReconciler_ReconcileLegacyFinalizers(t *testing.T) {
+	tests := []struct {
+		name           string
+		finalizers     []string
+		deleting       bool
+		wantFinalizers []string
+	}{
+		{
+			name:           "should replace the legacy finalizer",
+			finalizers:     []string{"legacy.cluster.x-k8s.io"},
+			wantFinalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		{
+			name:           "should remove the legacy finalizer next to the current one",
+			finalizers:     []string{clusterv1.ClusterFinalizer, "legacy.cluster.x-k8s.io", "other"},
+			wantFinalizers: []string{clusterv1.ClusterFinalizer, "other"},
+		},
+		{
+			name:           "should remove the legacy finalizer next to the current one on a Cluster being deleted",
+			finalizers:     []string{clusterv1.ClusterFinalizer, "legacy.cluster.x-k8s.io"},
+			deleting:       true,
+			wantFinalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		{
+			name:           "should leave the legacy finalizer alone on a Cluster being deleted without the current one",
+			finalizers:     []string{"legacy.cluster.x-k8s.io"},
+			deleting:       true,
+			wantFinalizers: []string{"legacy.cluster.x-k8s.io"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cluster",
+					Namespace:  "test",
+					Finalizers: tt.finalizers,
+				},
+			}
+			if tt.deleting {
+				cluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+
+			r := &ClusterReconciler{
+				Log:              log.Log,
+				LegacyFinalizers: []string{"legacy.cluster.x-k8s.io"},
+			}
+			r.migrateLegacyFinalizers(cluster)
+			g.Expect(cluster.Finalizers).To(ConsistOf(tt.wantFinalizers))
+		})
+	}
+}
+
+func TestClusterReconciler_ReconcileNamespaceTerminating(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceTerminating,
+		},
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).ownedBy(cluster).build()
+
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, namespace, cluster, &md)
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The Cluster is handled as being deleted: its descendants are deleted.
+	_, err := r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ensureNoDescendants(ctx, r, cluster)).To(Succeed())
+
+	got := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(conditions.IsFalse(got, clusterv1.NamespaceActiveCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(got, clusterv1.NamespaceActiveCondition)).To(Equal(clusterv1.NamespaceTerminatingReason))
+
+	// Without descendants left, the Cluster keeps its finalizer until it is deleted itself.
+	_, err = r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(got.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+func TestClusterReconciler_reconcileExtraPhases(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+
+	var called []string
+	r := &ClusterReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+		ExtraReconcilePhases: []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
+			func(context.Context, *clusterv1.Cluster) (ctrl.Result, error) {
+				called = append(called, "first")
+				return ctrl.Result{RequeueAfter: time.Second}, nil
+			},
+			func(context.Context, *clusterv1.Cluster) (ctrl.Result, error) {
+				called = append(called, "second")
+				return ctrl.Result{RequeueAfter: time.Hour}, nil
+			},
+		},
+	}
+
+	// The built-in phases ask to requeue once the control plane endpoint is set, while the
+	// custom phases ask to requeue sooner or later; the soonest requeue is honored.
+	res, _ := r.reconcile(ctx, cluster)
+	g.Expect(called).To(Equal([]string{"first", "second"}))
+	g.Expect(res.RequeueAfter).To(Equal(time.Second))
+}
+
+// recordingTracer is a Tracer recording the spans started and ended.
+type recordingTracer struct {
+	started    []string
+	ended      []string
+	attributes map[string]map[string]string
+}
+
+// recordingSpanKey is the context key of the name of the span started by a recordingTracer.
+type recordingSpanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	t.started = append(t.started, name)
+	if t.attributes == nil {
+		t.attributes = map[string]map[string]string{}
+	}
+	t.attributes[name] = attributes
+	return context.WithValue(ctx, recordingSpanKey{}, name), &recordingSpan{tracer: t, name: name}
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	name   string
+}
+
+func (s *recordingSpan) End() {
+	s.tracer.ended = append(s.tracer.ended, s.name)
+}
+
+func TestClusterReconciler_ReconcileTracing(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+
+	var extraPhaseSpan interface{}
+	tracer := &recordingTracer{}
+	r := &ClusterReconciler{
+		Client:   helpers.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+		Tracer:   tracer,
+		ExtraReconcilePhases: []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
+			func(ctx context.Context, _ *clusterv1.Cluster) (ctrl.Result, error) {
+				extraPhaseSpan = ctx.Value(recordingSpanKey{})
+				return ctrl.Result{}, nil
+			},
+		},
+	}
+
+	_, _ = r.Reconcile(ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+
+	// A span is emitted for the reconciliation and for each phase, and all of them are ended.
+	g.Expect(tracer.started).To(ContainElement("Reconcile"))
+	for _, phase := range []string{
+		"reconcileReferences",
+		"listDescendants",
+		"reconcileDescendantOwnerReferences",
+		"reconcileInfrastructure",
+		"reconcileControlPlane",
+		"reconcileKubeconfig",
+		"reconcileControlPlaneReachable",
+		"ExtraReconcilePhases[0]",
+	} {
+		g.Expect(tracer.started).To(ContainElement(phase))
+		g.Expect(tracer.attributes[phase]).To(Equal(map[string]string{
+			"cluster":   cluster.Name,
+			"namespace": cluster.Namespace,
+			"phase":     phase,
+		}))
+	}
+	g.Expect(tracer.ended).To(ConsistOf(tracer.started))
+	g.Expect(tracer.ended[len(tracer.ended)-1]).To(Equal("Reconcile"))
+
+	// Phases are called with the context carrying their own span.
+	g.Expect(extraPhaseSpan).To(Equal("ExtraReconcilePhases[0]"))
+}
+
+func TestClusterReconciler_reconcileRequeuePhase(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxRequeueAfter time.Duration
+		wantPhase       string
+	}{
+		{
+			name:      "should report the phase with the soonest requeue",
+			wantPhase: "ExtraReconcilePhases[1]",
+		},
+		{
+			name:            "should report the requeue cap if it applies",
+			maxRequeueAfter: time.Millisecond,
+			wantPhase:       "maxRequeueAfter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test",
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client:          fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+				Log:             log.Log,
+				recorder:        record.NewFakeRecorder(32),
+				MaxRequeueAfter: tt.maxRequeueAfter,
+				ExtraReconcilePhases: []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
+					func(context.Context, *clusterv1.Cluster) (ctrl.Result, error) {
+						return ctrl.Result{RequeueAfter: time.Hour}, nil
+					},
+					func(context.Context, *clusterv1.Cluster) (ctrl.Result, error) {
+						return ctrl.Result{RequeueAfter: time.Second}, nil
+					},
+				},
+			}
+
+			_, _ = r.reconcile(ctx, cluster)
+			g.Expect(cluster.Annotations).To(HaveKeyWithValue(clusterv1.RequeuePhaseAnnotation, tt.wantPhase))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileMaxRequeueAfter(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client:          fake.NewFakeClientWithScheme(scheme.Scheme, cluster),
+		Log:             log.Log,
+		recorder:        record.NewFakeRecorder(32),
+		MaxRequeueAfter: time.Second,
+		ExtraReconcilePhases: []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
+			func(context.Context, *clusterv1.Cluster) (ctrl.Result, error) {
+				return ctrl.Result{RequeueAfter: time.Hour}, nil
+			},
+		},
+	}
+
+	// The requeue asked by the phase is capped to the configured maximum.
+	res, _ := r.reconcile(ctx, cluster)
+	g.Expect(res.RequeueAfter).To(Equal(time.Second))
+
+	// Sooner requeues are not affected.
+	g.Expect(r.applyMaxRequeueAfter(ctrl.Result{RequeueAfter: time.Millisecond}).RequeueAfter).To(Equal(time.Millisecond))
+}
+
+func TestClusterReconciler_reconcileDeleteBootstrapConfigs(t *testing.T) {
+	bootstrapConfigKind := schema.GroupVersionKind{
+		Group:   "bootstrap.cluster.x-k8s.io",
+		Version: "v1alpha3",
+		Kind:    "BootstrapMachine",
+	}
+
+	tests := []struct {
+		name                 string
+		bootstrapConfigKinds []schema.GroupVersionKind
+		wantFinalizer        bool
+	}{
+		{
+			name:          "bootstrap configs not taken into account, should remove the finalizer",
+			wantFinalizer: false,
+		},
+		{
+			name:                 "bootstrap configs taken into account, should requeue and keep the finalizer",
+			bootstrapConfigKinds: []schema.GroupVersionKind{bootstrapConfigKind},
+			wantFinalizer:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cluster",
+					Namespace:  "test",
+					Finalizers: []string{clusterv1.ClusterFinalizer},
+				},
+			}
+			bootstrapConfig := &unstructured.Unstructured{}
+			bootstrapConfig.SetGroupVersionKind(bootstrapConfigKind)
+			bootstrapConfig.SetName("test-bootstrap-config")
+			bootstrapConfig.SetNamespace(cluster.Namespace)
+			bootstrapConfig.SetLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name})
+
+			r := &ClusterReconciler{
+				Client:               fake.NewFakeClientWithScheme(scheme.Scheme, cluster, bootstrapConfig),
+				Log:                  log.Log,
+				BootstrapConfigKinds: tt.bootstrapConfigKinds,
+				recorder:             record.NewFakeRecorder(32),
+			}
+
+			res, err := r.reconcileDelete(ctx, cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantFinalizer {
+				g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+				g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+			} else {
+				g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileDeleteControlPlaneRequeue(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	controlPlane := &unstructured.Unstructured{}
+	controlPlane.SetAPIVersion("controlplane.cluster.x-k8s.io/v1alpha3")
+	controlPlane.SetKind("GenericControlPlane")
+	controlPlane.SetName("test-control-plane")
+	controlPlane.SetNamespace("test")
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: controlPlane.GetAPIVersion(),
+				Kind:       controlPlane.GetKind(),
+				Name:       controlPlane.GetName(),
+				Namespace:  controlPlane.GetNamespace(),
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, cluster, controlPlane),
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// No watch is registered for the control plane kind, so the Cluster must requeue on its own.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Once the control plane object is gone, the requeued reconciliation completes the deletion.
+	res, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(BeZero())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+func TestClusterReconciler_reconcileDeleteEvent(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	machine := newMachineBuilder().named("worker").inCluster(cluster).build()
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, cluster, &machine)
+	recorder := record.NewFakeRecorder(32)
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: recorder,
+	}
+
+	// The Cluster still has descendants, so no event is recorded.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// Once the descendants are gone, the finalizer is removed and the deletion is recorded.
+	g.Expect(c.Delete(ctx, &machine)).To(Succeed())
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("ClusterDeleted"))
+}
+
+func TestClusterReconciler_reconcileControlPlaneResync(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha3",
+				Kind:       "InfrastructureMachine",
+				Name:       "test-controlplane",
+			},
+		},
+	}
+	controlPlane := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+			"metadata": map[string]interface{}{
+				"name":      "test-controlplane",
+				"namespace": "test",
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, external.TestGenericInfrastructureCRD.DeepCopy(), cluster, controlPlane)
+	r := &ClusterReconciler{
+		Client:                   c,
+		Log:                      log.Log,
+		scheme:                   scheme.Scheme,
+		ControlPlaneResyncPeriod: time.Second,
+	}
+	setReady := func(ready bool) {
+		g.Expect(c.Get(ctx, util.ObjectKey(controlPlane), controlPlane)).To(Succeed())
+		g.Expect(unstructured.SetNestedField(controlPlane.Object, ready, "status", "ready")).To(Succeed())
+		g.Expect(c.Update(ctx, controlPlane)).To(Succeed())
+	}
+
+	// A ready control plane does not require re-syncing the Cluster.
+	g.Expect(r.reconcileControlPlane(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+	g.Expect(r.applyControlPlaneResync(cluster, ctrl.Result{})).To(Equal(ctrl.Result{}))
+
+	// A control plane going not ready is reflected, and the Cluster is re-synced until it gets ready again.
+	setReady(false)
+	g.Expect(r.reconcileControlPlane(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+	g.Expect(r.applyControlPlaneResync(cluster, ctrl.Result{})).To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+	g.Expect(r.applyControlPlaneResync(cluster, ctrl.Result{RequeueAfter: time.Hour})).To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+
+	// The control plane getting ready again is reflected at the next re-sync.
+	setReady(true)
+	g.Expect(r.reconcileControlPlane(ctx, cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ControlPlaneReadyCondition)).To(BeTrue())
+	g.Expect(r.applyControlPlaneResync(cluster, ctrl.Result{})).To(Equal(ctrl.Result{}))
+}
+
+func TestClusterReconciler_reconcileDeleteCustomDescendantKind(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	nodePoolKind := schema.GroupVersionKind{
+		Group:   "infrastructure.cluster.x-k8s.io",
+		Version: "v1alpha3",
+		Kind:    "NodePool",
+	}
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	newNodePool := func(name string, owned bool) *unstructured.Unstructured {
+		nodePool := &unstructured.Unstructured{}
+		nodePool.SetGroupVersionKind(nodePoolKind)
+		nodePool.SetName(name)
+		nodePool.SetNamespace(cluster.Namespace)
+		nodePool.SetLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name})
+		if owned {
+			nodePool.SetOwnerReferences([]metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+				},
+			})
+		}
+		return nodePool
+	}
+	ownedNodePool := newNodePool("owned-nodepool", true)
+	unownedNodePool := newNodePool("unowned-nodepool", false)
+	controlPlane := newMachineBuilder().named("control-plane").inCluster(cluster).ownedBy(cluster).controlPlane().build()
+
+	c := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, ownedNodePool, unownedNodePool, &controlPlane),
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+	r.RegisterDescendantKind(nodePoolKind, nil)
+
+	// Owned descendants of the custom kind are deleted, before control plane Machines.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(c.deleted).To(Equal([]string{"owned-nodepool", "control-plane"}))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Remaining descendants of the custom kind block the finalizer removal.
+	res, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Once they are gone, the finalizer is removed.
+	g.Expect(c.Client.Delete(ctx, unownedNodePool)).To(Succeed())
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+// annotationsOnDeleteClient records the annotations of the objects being deleted, as persisted at the time of the Delete call.
+type annotationsOnDeleteClient struct {
+	client.Client
+	annotations map[string]map[string]string
+}
+
+func (c *annotationsOnDeleteClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	persisted := obj.DeepCopyObject()
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}, persisted); err != nil {
+		return err
+	}
+	persistedAccessor, err := meta.Accessor(persisted)
+	if err != nil {
+		return err
+	}
+	c.annotations[accessor.GetName()] = persistedAccessor.GetAnnotations()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestClusterReconciler_reconcileDeleteTransformer(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).ownedBy(cluster).build()
+	machine := newMachineBuilder().named("machine").inCluster(cluster).ownedBy(cluster).build()
+
+	c := &annotationsOnDeleteClient{
+		Client:      helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &md, &machine),
+		annotations: map[string]map[string]string{},
+	}
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+		DeleteTransformer: func(obj runtime.Object) {
+			accessor, err := meta.Accessor(obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			accessor.SetAnnotations(map[string]string{"snapshot": "true"})
+		},
+	}
+
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The transformer mutations are persisted before each descendant is deleted.
+	g.Expect(c.annotations).To(HaveLen(2))
+	g.Expect(c.annotations).To(HaveKeyWithValue("md", map[string]string{"snapshot": "true"}))
+	g.Expect(c.annotations).To(HaveKeyWithValue("machine", map[string]string{"snapshot": "true"}))
+}
+
+func TestClusterReconciler_patchClusterSortsConditions(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test",
+		},
+	}
+	c := helpers.NewFakeClientWithScheme(scheme.Scheme, cluster)
+	r := &ClusterReconciler{
+		Client: c,
+		Log:    log.Log,
+	}
+
+	patchHelper, err := patch.NewHelper(cluster, c)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Conditions are written in insertion order, regardless of their type.
+	cluster.Status.Conditions = clusterv1.Conditions{
+		*conditions.TrueCondition("Foo"),
+		*conditions.TrueCondition(clusterv1.InfrastructureReadyCondition),
+		*conditions.TrueCondition("Bar"),
+		*conditions.TrueCondition(clusterv1.ControlPlaneReadyCondition),
+	}
+	g.Expect(r.patchCluster(ctx, patchHelper, cluster)).To(Succeed())
+
+	// The Ready condition goes first, followed by the summarized conditions and by the other ones sorted by type.
+	got := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	var types []clusterv1.ConditionType
+	for _, condition := range got.Status.Conditions {
+		types = append(types, condition.Type)
+	}
+	g.Expect(types).To(Equal([]clusterv1.ConditionType{
+		clusterv1.ReadyCondition,
+		clusterv1.ControlPlaneReadyCondition,
+		clusterv1.InfrastructureReadyCondition,
+		"Bar",
+		"Foo",
+	}))
+}
+
+func TestClusterReconciler_reconcileDeleteControlPlaneEndpointTerminating(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	controlPlane := &unstructured.Unstructured{}
+	controlPlane.SetAPIVersion("controlplane.cluster.x-k8s.io/v1alpha3")
+	controlPlane.SetKind("GenericControlPlane")
+	controlPlane.SetName("test-control-plane")
+	controlPlane.SetNamespace("test")
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{
+				Host: "1.2.3.4",
+				Port: 6443,
+			},
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: controlPlane.GetAPIVersion(),
+				Kind:       controlPlane.GetKind(),
+				Name:       controlPlane.GetName(),
+				Namespace:  controlPlane.GetNamespace(),
+			},
+		},
+	}
+
+	r := &ClusterReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, cluster, controlPlane),
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The condition turns false as soon as the deletion starts, while the control plane object still exists.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneEndpointServingCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneEndpointServingCondition)).To(Equal(clusterv1.ControlPlaneEndpointTerminatingReason))
+	g.Expect(conditions.Get(cluster, clusterv1.ControlPlaneEndpointServingCondition).Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+
+	// Once the control plane object is gone, the condition reports the endpoint as terminated;
+	// the control plane endpoint in the spec is left untouched.
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ControlPlaneEndpointServingCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ControlPlaneEndpointServingCondition)).To(Equal(clusterv1.ControlPlaneEndpointTerminatedReason))
+	g.Expect(cluster.Status.ControlPlaneReady).To(BeFalse())
+	g.Expect(cluster.Spec.ControlPlaneEndpoint.Host).To(Equal("1.2.3.4"))
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+// failingMachineSetListClient fails listing MachineSets.
+type failingMachineSetListClient struct {
+	client.Client
+}
+
+func (c *failingMachineSetListClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if _, ok := list.(*clusterv1.MachineSetList); ok {
+		return errors.New("failed to list MachineSets")
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestClusterReconciler_reconcileDeletePartialListFailure(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-cluster",
+			Namespace:  "test",
+			Finalizers: []string{clusterv1.ClusterFinalizer},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").inCluster(cluster).ownedBy(cluster).build()
+	ms := newMachineSetBuilder().named("ms").inCluster(cluster).ownedBy(cluster).build()
+	machine := newMachineBuilder().named("machine").inCluster(cluster).ownedBy(cluster).build()
+
+	recording := &deleteRecordingClient{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, &md, &ms, &machine),
+	}
+	c := &failingMachineSetListClient{Client: recording}
+
+	// The kinds listed successfully are returned along with the error.
+	descendants, err := listDescendants(ctx, c, cluster, 0)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(descendants.machineDeployments.Items).To(HaveLen(1))
+	g.Expect(descendants.machineSets.Items).To(BeEmpty())
+	g.Expect(descendants.workerMachines.Items).To(HaveLen(1))
+
+	r := &ClusterReconciler{
+		Client:   c,
+		Log:      log.Log,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The deletion makes progress on the kinds listed successfully, but the error is still surfaced
+	// and the finalizer is not removed.
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(recording.deleted).To(ConsistOf("md", "machine"))
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+}
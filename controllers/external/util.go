@@ -19,15 +19,19 @@ package external
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/storage/names"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util"
 )
 
 const (
@@ -49,6 +53,24 @@ func Get(ctx context.Context, c client.Client, ref *corev1.ObjectReference, name
 	return obj, nil
 }
 
+// StorageVersionReference returns a copy of the given reference using the storage version of the given
+// CustomResourceDefinition, or the reference itself if the CustomResourceDefinition does not serve its storage version.
+func StorageVersionReference(crd *apiextensionsv1.CustomResourceDefinition, ref *corev1.ObjectReference) *corev1.ObjectReference {
+	gvk := ref.GroupVersionKind()
+	for _, version := range crd.Spec.Versions {
+		if !version.Storage || !version.Served {
+			continue
+		}
+		if version.Name == gvk.Version {
+			return ref
+		}
+		storageRef := ref.DeepCopy()
+		storageRef.APIVersion = schema.GroupVersion{Group: gvk.Group, Version: version.Name}.String()
+		return storageRef
+	}
+	return ref
+}
+
 type CloneTemplateInput struct {
 	// Client is the controller runtime client.
 	// +required
@@ -205,6 +227,20 @@ func IsReady(obj *unstructured.Unstructured) (bool, error) {
 	return ready && found, nil
 }
 
+// RetryAfter returns the duration defined by the Status.RetryAfterSeconds field on an external object, which
+// providers can set to hint when to check the object again, e.g. when rate-limited; it returns 0 if not set.
+func RetryAfter(obj *unstructured.Unstructured) (time.Duration, error) {
+	seconds, found, err := unstructured.NestedInt64(obj.Object, "status", "retryAfterSeconds")
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to determine %v %q retryAfterSeconds",
+			obj.GroupVersionKind(), obj.GetName())
+	}
+	if !found || seconds <= 0 {
+		return 0, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
 // IsInitialized returns true if the Status.Initialized field on an external object is true.
 func IsInitialized(obj *unstructured.Unstructured) (bool, error) {
 	initialized, found, err := unstructured.NestedBool(obj.Object, "status", "initialized")
@@ -214,3 +250,17 @@ func IsInitialized(obj *unstructured.Unstructured) (bool, error) {
 	}
 	return initialized && found, nil
 }
+
+// GetControlPlaneEndpoint returns the Spec.ControlPlaneEndpoint field from an external object;
+// it returns a zero APIEndpoint if not set, and an error if the field is malformed.
+func GetControlPlaneEndpoint(obj *unstructured.Unstructured) (clusterv1.APIEndpoint, error) {
+	var endpoint clusterv1.APIEndpoint
+	if err := util.UnstructuredUnmarshalField(obj, &endpoint, "spec", "controlPlaneEndpoint"); err != nil {
+		if err == util.ErrUnstructuredFieldNotFound {
+			return clusterv1.APIEndpoint{}, nil
+		}
+		return clusterv1.APIEndpoint{}, errors.Wrapf(err, "failed to determine %v %q controlPlaneEndpoint",
+			obj.GroupVersionKind(), obj.GetName())
+	}
+	return endpoint, nil
+}
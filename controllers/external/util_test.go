@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -79,6 +80,61 @@ func TestGetResourceNotFound(t *testing.T) {
 	g.Expect(apierrors.IsNotFound(errors.Cause(err))).To(BeTrue())
 }
 
+func TestStorageVersionReference(t *testing.T) {
+	crd := TestGenericInfrastructureCRD.DeepCopy()
+	crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{
+		{
+			Name:   "v1alpha3",
+			Served: true,
+		},
+		{
+			Name:    "v1alpha4",
+			Served:  true,
+			Storage: true,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		apiVersion     string
+		wantAPIVersion string
+	}{
+		{
+			name:           "storage version is used if the version of the reference is not served",
+			apiVersion:     "infrastructure.cluster.x-k8s.io/v1alpha2",
+			wantAPIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+		},
+		{
+			name:           "storage version is preferred to another served version of the reference",
+			apiVersion:     "infrastructure.cluster.x-k8s.io/v1alpha3",
+			wantAPIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+		},
+		{
+			name:           "storage version of the reference is used as is",
+			apiVersion:     "infrastructure.cluster.x-k8s.io/v1alpha4",
+			wantAPIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ref := &corev1.ObjectReference{
+				Kind:       "InfrastructureMachine",
+				APIVersion: tt.apiVersion,
+				Name:       "test",
+				Namespace:  "test",
+			}
+
+			got := StorageVersionReference(crd, ref)
+			g.Expect(got.APIVersion).To(Equal(tt.wantAPIVersion))
+			g.Expect(got.Name).To(Equal(ref.Name))
+			g.Expect(ref.APIVersion).To(Equal(tt.apiVersion))
+		})
+	}
+}
+
 func TestCloneTemplateResourceNotFound(t *testing.T) {
 	g := NewWithT(t)
 
@@ -313,3 +369,71 @@ func TestCloneTemplateMissingSpecTemplate(t *testing.T) {
 	})
 	g.Expect(err).To(HaveOccurred())
 }
+
+func TestGetControlPlaneEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    map[string]interface{}
+		want    clusterv1.APIEndpoint
+		wantErr bool
+	}{
+		{
+			name: "endpoint set",
+			spec: map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": int64(6443),
+				},
+			},
+			want: clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443},
+		},
+		{
+			name: "endpoint not set",
+			spec: map[string]interface{}{},
+			want: clusterv1.APIEndpoint{},
+		},
+		{
+			name: "malformed port",
+			spec: map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{
+					"host": "1.2.3.4",
+					"port": "not-a-port",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed endpoint",
+			spec: map[string]interface{}{
+				"controlPlaneEndpoint": "1.2.3.4:6443",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "InfrastructureCluster",
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+					"metadata": map[string]interface{}{
+						"name":      "test",
+						"namespace": "test",
+					},
+					"spec": tt.spec,
+				},
+			}
+
+			endpoint, err := GetControlPlaneEndpoint(obj)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(endpoint).To(Equal(tt.want))
+		})
+	}
+}
@@ -275,14 +275,18 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 	}
 
 	if isDeleteNodeAllowed {
-		// Drain node before deletion.
+		// Drain node before deletion, unless the node drain timeout has been exceeded.
 		if _, exists := m.ObjectMeta.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; !exists {
-			logger.Info("Draining node", "node", m.Status.NodeRef.Name)
-			if err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name, m.Name); err != nil {
-				r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
-				return ctrl.Result{}, err
+			if nodeDrainTimeoutExceeded(m) {
+				logger.Info("Skipping node drain, the node drain timeout has been exceeded", "node", m.Status.NodeRef.Name, "timeout", m.Spec.NodeDrainTimeout.Duration)
+			} else {
+				logger.Info("Draining node", "node", m.Status.NodeRef.Name)
+				if err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name, m.Name); err != nil {
+					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
+					return ctrl.Result{}, err
+				}
+				r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
 			}
-			r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
 		}
 	}
 
@@ -347,6 +351,15 @@ func (r *MachineReconciler) isDeleteNodeAllowed(ctx context.Context, cluster *cl
 	}
 }
 
+// nodeDrainTimeoutExceeded returns true if the Machine defines a NodeDrainTimeout, and more time than it
+// has elapsed since the Machine deletion started.
+func nodeDrainTimeoutExceeded(m *clusterv1.Machine) bool {
+	if m.Spec.NodeDrainTimeout == nil || m.Spec.NodeDrainTimeout.Duration <= 0 || m.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return time.Since(m.DeletionTimestamp.Time) > m.Spec.NodeDrainTimeout.Duration
+}
+
 func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string, machineName string) error {
 	logger := r.Log.WithValues("machine", machineName, "node", nodeName, "cluster", cluster.Name, "namespace", cluster.Namespace)
 
@@ -972,3 +972,53 @@ func TestIsDeleteNodeAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeDrainTimeoutExceeded(t *testing.T) {
+	deletedAnHourAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name              string
+		nodeDrainTimeout  *metav1.Duration
+		deletionTimestamp *metav1.Time
+		want              bool
+	}{
+		{
+			name:              "no node drain timeout",
+			deletionTimestamp: &deletedAnHourAgo,
+			want:              false,
+		},
+		{
+			name:             "machine not being deleted",
+			nodeDrainTimeout: &metav1.Duration{Duration: time.Minute},
+			want:             false,
+		},
+		{
+			name:              "node drain timeout not exceeded",
+			nodeDrainTimeout:  &metav1.Duration{Duration: 2 * time.Hour},
+			deletionTimestamp: &deletedAnHourAgo,
+			want:              false,
+		},
+		{
+			name:              "node drain timeout exceeded",
+			nodeDrainTimeout:  &metav1.Duration{Duration: time.Minute},
+			deletionTimestamp: &deletedAnHourAgo,
+			want:              true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: tt.deletionTimestamp,
+				},
+				Spec: clusterv1.MachineSpec{
+					NodeDrainTimeout: tt.nodeDrainTimeout,
+				},
+			}
+			g.Expect(nodeDrainTimeoutExceeded(m)).To(Equal(tt.want))
+		})
+	}
+}
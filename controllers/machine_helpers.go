@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -50,6 +51,33 @@ func getActiveMachinesInCluster(ctx context.Context, c client.Client, namespace,
 	return machines, nil
 }
 
+// getControlPlaneMachinePoolsInCluster returns all of the active control plane MachinePool objects
+// that belong to the cluster with given namespace/name
+func getControlPlaneMachinePoolsInCluster(ctx context.Context, c client.Client, namespace, name string) ([]*expv1.MachinePool, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	machinePoolList := &expv1.MachinePoolList{}
+	labels := map[string]string{clusterv1.ClusterLabelName: name}
+
+	if err := c.List(ctx, machinePoolList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, errors.Wrap(err, "failed to list machine pools")
+	}
+
+	machinePools := []*expv1.MachinePool{}
+	for i := range machinePoolList.Items {
+		mp := &machinePoolList.Items[i]
+		if _, ok := mp.Labels[clusterv1.MachineControlPlaneLabelName]; !ok {
+			continue
+		}
+		if mp.DeletionTimestamp.IsZero() {
+			machinePools = append(machinePools, mp)
+		}
+	}
+	return machinePools, nil
+}
+
 // hasMatchingLabels verifies that the Label Selector matches the given Labels
 func hasMatchingLabels(matchSelector metav1.LabelSelector, matchLabels map[string]string) bool {
 	// This should never fail, validating webhook should catch this first
@@ -302,6 +302,12 @@ func (t *healthCheckTarget) remediate(ctx context.Context, logger logr.Logger, c
 		return nil
 	}
 
+	// If the remediation of the machine is disabled, e.g. by its cluster, it should be skipped
+	if _, ok := t.Machine.Annotations[clusterv1.SkipRemediationAnnotation]; ok {
+		logger.Info("Target has remediation disabled, skipping remediation")
+		return nil
+	}
+
 	logger.Info("Deleting target machine")
 	if err := c.Delete(ctx, t.Machine); err != nil {
 		r.Eventf(
@@ -346,6 +346,9 @@ func TestRemediate(t *testing.T) {
 	workerMachine := newTestMachine("worker-machine", namespace, clusterName, workerNode.Name, labels)
 	workerMachine.SetOwnerReferences(machineSetORs)
 	workerMachineUnowned := newTestMachine("worker-machine", namespace, clusterName, workerNode.Name, labels)
+	workerMachineSkipped := newTestMachine("worker-machine", namespace, clusterName, workerNode.Name, labels)
+	workerMachineSkipped.SetOwnerReferences(machineSetORs)
+	workerMachineSkipped.SetAnnotations(map[string]string{clusterv1.SkipRemediationAnnotation: clusterName})
 
 	controlPlaneNode := newTestNode("control-plane-node")
 	if controlPlaneNode.Labels == nil {
@@ -384,6 +387,14 @@ func TestRemediate(t *testing.T) {
 			expectDeleted: true,
 			expectEvents:  []string{EventMachineDeleted},
 		},
+		{
+			name:          "when the machine has remediation disabled",
+			node:          workerNode,
+			machine:       workerMachineSkipped,
+			expectErr:     false,
+			expectDeleted: false,
+			expectEvents:  []string{},
+		},
 		{
 			name:          "when the node is a control plane node",
 			node:          controlPlaneNode,
@@ -64,6 +64,24 @@ var (
 		[]string{"cluster", "namespace"},
 	)
 
+	// ClusterFinalizerAdded is a metric counting the number of times the
+	// cluster finalizer has been added to a Cluster.
+	ClusterFinalizerAdded = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "capi_cluster_finalizer_added_total",
+			Help: "Total number of times the finalizer has been added to a Cluster.",
+		},
+	)
+
+	// ClusterFinalizerRemoved is a metric counting the number of times the
+	// cluster finalizer has been removed from a Cluster.
+	ClusterFinalizerRemoved = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "capi_cluster_finalizer_removed_total",
+			Help: "Total number of times the finalizer has been removed from a Cluster.",
+		},
+	)
+
 	// MachineBootstrapReady is a metric that is set to 1 if machine bootstrap
 	// is ready and 0 if it is not.
 	MachineBootstrapReady = prometheus.NewGaugeVec(
@@ -101,6 +119,8 @@ func init() {
 		ClusterInfrastructureReady,
 		ClusterKubeconfigReady,
 		ClusterFailureSet,
+		ClusterFinalizerAdded,
+		ClusterFinalizerRemoved,
 		MachineBootstrapReady,
 		MachineInfrastructureReady,
 		MachineNodeReady,
@@ -17,12 +17,17 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"time"
+
 	. "github.com/onsi/gomega"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
@@ -89,3 +94,27 @@ func fakeMachineNodeRef(m *clusterv1.Machine) {
 	}
 	Expect(testEnv.Status().Patch(ctx, m, patchMachine)).To(Succeed())
 }
+
+// ensureNoDescendants waits for the descendants of the given Cluster, as listed by the Cluster controller, to be gone;
+// it returns an error naming the descendants still there when the context is done, or after timeout if it has no deadline.
+func ensureNoDescendants(ctx context.Context, r *ClusterReconciler, cluster *clusterv1.Cluster) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var descendants clusterDescendants
+	var listErr error
+	err := wait.PollImmediateUntil(100*time.Millisecond, func() (bool, error) {
+		descendants, listErr = r.listDescendants(ctx, cluster)
+		return listErr == nil && descendants.length() == 0, nil
+	}, ctx.Done())
+	if err == nil {
+		return nil
+	}
+	if listErr != nil {
+		return errors.Wrapf(listErr, "failed to list descendants of Cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	return errors.Errorf("Cluster %s/%s still has descendants: %s", cluster.Namespace, cluster.Name, descendants.descendantNames())
+}
@@ -18,6 +18,7 @@ package errors
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -55,3 +56,36 @@ func IsRequeueAfter(err error) bool {
 	_, ok := errors.Cause(err).(HasRequeueAfterError)
 	return ok
 }
+
+// DeletionBlockedError represents that the deletion of an object is blocked by other objects
+// which could not be deleted, e.g. the control plane or the infrastructure object of a Cluster.
+type DeletionBlockedError struct {
+	// Kind is the kind of the objects blocking the deletion.
+	Kind string
+
+	// Names are the names of the objects blocking the deletion.
+	Names []string
+
+	// Err is the error preventing the deletion of the blocking objects, if any.
+	Err error
+}
+
+// Error implements the error interface
+func (e *DeletionBlockedError) Error() string {
+	msg := fmt.Sprintf("deletion blocked by %s %s", e.Kind, strings.Join(e.Names, ", "))
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the error preventing the deletion of the blocking objects, if any.
+func (e *DeletionBlockedError) Unwrap() error {
+	return e.Err
+}
+
+// IsDeletionBlocked returns true if the error is a DeletionBlockedError.
+func IsDeletionBlocked(err error) bool {
+	_, ok := errors.Cause(err).(*DeletionBlockedError)
+	return ok
+}
@@ -26,6 +26,9 @@ import (
 const (
 	// MachinePoolFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	MachinePoolFinalizer = "machinepool.exp.cluster.x-k8s.io"
+
+	// MachinePoolNameLabel is the label set on Machines backed by a MachinePool, with the name of the MachinePool.
+	MachinePoolNameLabel = "cluster.x-k8s.io/pool-name"
 )
 
 // ANCHOR: MachinePoolSpec
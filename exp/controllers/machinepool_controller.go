@@ -30,6 +30,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -162,11 +163,14 @@ func (r *MachinePoolReconciler) reconcile(ctx context.Context, cluster *clusterv
 	logger = logger.WithValues("cluster", cluster.Name)
 
 	// Ensure the MachinePool is owned by the Cluster it belongs to.
+	// NOTE: The owner reference blocks the deletion of the Cluster, consistently with the one maintained
+	// by the Cluster controller, so the two controllers don't fight over it.
 	mp.OwnerReferences = util.EnsureOwnerRef(mp.OwnerReferences, metav1.OwnerReference{
-		APIVersion: cluster.APIVersion,
-		Kind:       cluster.Kind,
-		Name:       cluster.Name,
-		UID:        cluster.UID,
+		APIVersion:         cluster.APIVersion,
+		Kind:               cluster.Kind,
+		Name:               cluster.Name,
+		UID:                cluster.UID,
+		BlockOwnerDeletion: pointer.BoolPtr(true),
 	})
 
 	// If the MachinePool doesn't have a finalizer, add one.
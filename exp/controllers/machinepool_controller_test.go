@@ -206,10 +206,11 @@ func TestMachinePoolOwnerReference(t *testing.T) {
 			m: machinePoolValidCluster,
 			expectedOR: []metav1.OwnerReference{
 				{
-					APIVersion: testCluster.APIVersion,
-					Kind:       testCluster.Kind,
-					Name:       testCluster.Name,
-					UID:        testCluster.UID,
+					APIVersion:         testCluster.APIVersion,
+					Kind:               testCluster.Kind,
+					Name:               testCluster.Name,
+					UID:                testCluster.UID,
+					BlockOwnerDeletion: pointer.BoolPtr(true),
 				},
 			},
 		},
@@ -23,9 +23,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog"
 	"k8s.io/klog/klogr"
@@ -65,6 +67,39 @@ var (
 	syncPeriod                    time.Duration
 	webhookPort                   int
 	healthAddr                    string
+
+	// Cluster controller flags
+	clusterControlPlaneProbe                  bool
+	clusterControlPlaneProbeTimeout           time.Duration
+	clusterSummaryConditions                  []string
+	clusterNegativePolarityConditions         []string
+	clusterMustBeTrueConditions               []string
+	clusterConditionsToMirror                 []string
+	clusterDeleteWorkerMachinesInBulk         bool
+	clusterParallelMachineDeletion            bool
+	clusterMinDescendantAgeBeforeDeletion     time.Duration
+	clusterValidateReferencedKinds            bool
+	clusterClearFailuresOnRecovery            bool
+	clusterFilterDescendantsByUID             bool
+	clusterPropagateLabels                    bool
+	clusterExportKubeconfigConfigMap          bool
+	clusterDeleteSecrets                      bool
+	clusterSecretNamespaces                   []string
+	clusterListDescendantsTimeout             time.Duration
+	clusterInfrastructureNotFoundRequeueAfter time.Duration
+	clusterInfrastructureNotFoundThreshold    int
+	clusterRecreateDeletedInfrastructure      bool
+	clusterControlPlaneDeletingGracePeriod    time.Duration
+	clusterControlPlaneResyncPeriod           time.Duration
+	clusterResetControlPlaneInitialized       bool
+	clusterMaxDescendantsWarnThreshold        int
+	clusterMaxRequeueAfter                    time.Duration
+	clusterPhaseFromConditions                bool
+	clusterBootstrapConfigKinds               []string
+	clusterLegacyFinalizers                   []string
+	clusterReadOnly                           bool
+	clusterExportDescendantTopology           bool
+	clusterDescendantChurnThreshold           int
 )
 
 func init() {
@@ -128,6 +163,99 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.BoolVar(&clusterControlPlaneProbe, "cluster-control-plane-probe", false,
+		"Probe the API server of the workload clusters and report the result in the ControlPlaneReachable condition.")
+
+	fs.DurationVar(&clusterControlPlaneProbeTimeout, "cluster-control-plane-probe-timeout", 5*time.Second,
+		"The timeout used when probing the API server of the workload clusters (duration string)")
+
+	fs.StringSliceVar(&clusterSummaryConditions, "cluster-summary-conditions", nil,
+		"Comma-separated list of the conditions summarized into the Ready condition of the clusters, by priority. If unspecified, ControlPlaneReady and InfrastructureReady are summarized.")
+
+	fs.StringSliceVar(&clusterNegativePolarityConditions, "cluster-negative-polarity-conditions", nil,
+		"Comma-separated list of the cluster conditions signaling a problem when true, e.g. Degraded.")
+
+	fs.StringSliceVar(&clusterMustBeTrueConditions, "cluster-must-be-true-conditions", nil,
+		"Comma-separated list of the cluster conditions lowering the Ready condition of the clusters when unknown.")
+
+	fs.StringSliceVar(&clusterConditionsToMirror, "cluster-conditions-to-mirror", nil,
+		"Comma-separated list of the conditions of the infrastructure and control plane objects mirrored into the clusters. If unspecified, their Ready condition is mirrored.")
+
+	fs.BoolVar(&clusterDeleteWorkerMachinesInBulk, "cluster-delete-worker-machines-in-bulk", false,
+		"Delete the worker machines of a cluster being deleted with a single call, when they are not owned by other objects.")
+
+	fs.BoolVar(&clusterParallelMachineDeletion, "cluster-parallel-machine-deletion", false,
+		"Delete the control plane and the worker machines of a cluster being deleted together, instead of deleting the control plane machines last.")
+
+	fs.DurationVar(&clusterMinDescendantAgeBeforeDeletion, "cluster-min-descendant-age-before-deletion", 0,
+		"The minimum age of the descendants of a cluster being deleted before they are deleted (duration string)")
+
+	fs.BoolVar(&clusterValidateReferencedKinds, "cluster-validate-referenced-kinds", false,
+		"Report clusters whose control plane or infrastructure reference points to a template.")
+
+	fs.BoolVar(&clusterClearFailuresOnRecovery, "cluster-clear-failures-on-recovery", false,
+		"Clear the failures of a cluster once the object they have been detected from recovers.")
+
+	fs.BoolVar(&clusterFilterDescendantsByUID, "cluster-filter-descendants-by-uid", false,
+		"Ignore the descendants of a cluster labeled with the UID of another cluster.")
+
+	fs.BoolVar(&clusterPropagateLabels, "cluster-propagate-labels", false,
+		"Propagate the labels of the clusters to their descendants.")
+
+	fs.BoolVar(&clusterExportKubeconfigConfigMap, "cluster-export-kubeconfig-configmap", false,
+		"Export the server URL and the name of the clusters to a ConfigMap; credentials are never exported.")
+
+	fs.BoolVar(&clusterDeleteSecrets, "cluster-delete-secrets", false,
+		"Delete the Secrets owned by a cluster as a last step of its deletion.")
+
+	fs.StringSliceVar(&clusterSecretNamespaces, "cluster-secret-namespaces", nil,
+		"Comma-separated list of additional namespaces where the Secrets labeled with the name of a cluster are deleted as a last step of its deletion.")
+
+	fs.DurationVar(&clusterListDescendantsTimeout, "cluster-list-descendants-timeout", 30*time.Second,
+		"The timeout for each call listing the descendants of a cluster (duration string)")
+
+	fs.DurationVar(&clusterInfrastructureNotFoundRequeueAfter, "cluster-infrastructure-not-found-requeue-after", 10*time.Second,
+		"Interval at which the infrastructure object of a cluster is checked again when not found (duration string)")
+
+	fs.IntVar(&clusterInfrastructureNotFoundThreshold, "cluster-infrastructure-not-found-threshold", 1,
+		"Number of consecutive reconciliations the infrastructure object of a provisioned cluster must be not found for before it is reported as deleted")
+
+	fs.BoolVar(&clusterRecreateDeletedInfrastructure, "cluster-recreate-deleted-infrastructure", false,
+		"Recreate the deleted infrastructure object of a provisioned cluster from the template named by its cluster.x-k8s.io/infrastructure-template annotation.")
+
+	fs.DurationVar(&clusterControlPlaneDeletingGracePeriod, "cluster-control-plane-deleting-grace-period", 10*time.Second,
+		"How long the control plane object of a cluster being deleted must have been in deletion before it is reported as deleting (duration string)")
+
+	fs.DurationVar(&clusterControlPlaneResyncPeriod, "cluster-control-plane-resync-period", 15*time.Second,
+		"Interval at which a cluster is reconciled while its control plane is not ready (duration string)")
+
+	fs.BoolVar(&clusterResetControlPlaneInitialized, "cluster-reset-control-plane-initialized", false,
+		"Reset the control plane initialized status of a cluster without a control plane provider when none of its control plane machines has a node.")
+
+	fs.IntVar(&clusterMaxDescendantsWarnThreshold, "cluster-max-descendants-warn-threshold", 0,
+		"Number of descendants of a cluster above which a warning is reported. If zero, no check applies.")
+
+	fs.DurationVar(&clusterMaxRequeueAfter, "cluster-max-requeue-after", 0,
+		"The maximum interval at which a cluster asking to be requeued is reconciled again. If zero, no cap applies (duration string)")
+
+	fs.BoolVar(&clusterPhaseFromConditions, "cluster-phase-from-conditions", false,
+		"Derive the phase of the clusters from their Ready condition only.")
+
+	fs.StringSliceVar(&clusterBootstrapConfigKinds, "cluster-bootstrap-config-kinds", nil,
+		"Comma-separated list of the bootstrap config kinds counted as descendants of a cluster, in the Kind.version.group format (e.g. KubeadmConfig.v1alpha3.bootstrap.cluster.x-k8s.io).")
+
+	fs.StringSliceVar(&clusterLegacyFinalizers, "cluster-legacy-finalizers", nil,
+		"Comma-separated list of the finalizers set on clusters by other versions of Cluster API, replaced by the cluster.cluster.x-k8s.io finalizer.")
+
+	fs.BoolVar(&clusterReadOnly, "cluster-read-only", false,
+		"Compute the status of the clusters without issuing any write.")
+
+	fs.BoolVar(&clusterExportDescendantTopology, "cluster-export-descendant-topology", false,
+		"Export a summary of the descendants of each cluster in an annotation.")
+
+	fs.IntVar(&clusterDescendantChurnThreshold, "cluster-descendant-churn-threshold", 5,
+		"Number of times a descendant of a cluster being deleted can be deleted before it is reported as churning")
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -214,9 +342,45 @@ func setupReconcilers(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	bootstrapConfigKinds, err := groupVersionKinds(clusterBootstrapConfigKinds)
+	if err != nil {
+		setupLog.Error(err, "invalid bootstrap config kinds")
+		os.Exit(1)
+	}
 	if err := (&controllers.ClusterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Cluster"),
+		Client:                             mgr.GetClient(),
+		Log:                                ctrl.Log.WithName("controllers").WithName("Cluster"),
+		EnableControlPlaneProbe:            clusterControlPlaneProbe,
+		ControlPlaneProbeTimeout:           clusterControlPlaneProbeTimeout,
+		SummaryConditions:                  conditionTypes(clusterSummaryConditions),
+		NegativePolarityConditions:         conditionTypes(clusterNegativePolarityConditions),
+		MustBeTrueConditions:               conditionTypes(clusterMustBeTrueConditions),
+		ConditionsToMirror:                 conditionTypes(clusterConditionsToMirror),
+		DeleteWorkerMachinesInBulk:         clusterDeleteWorkerMachinesInBulk,
+		ParallelMachineDeletion:            clusterParallelMachineDeletion,
+		MinDescendantAgeBeforeDeletion:     clusterMinDescendantAgeBeforeDeletion,
+		ValidateReferencedKinds:            clusterValidateReferencedKinds,
+		ClearFailuresOnRecovery:            clusterClearFailuresOnRecovery,
+		FilterDescendantsByClusterUID:      clusterFilterDescendantsByUID,
+		PropagateLabels:                    clusterPropagateLabels,
+		ExportKubeconfigConfigMap:          clusterExportKubeconfigConfigMap,
+		DeleteClusterSecrets:               clusterDeleteSecrets,
+		SecretNamespaces:                   clusterSecretNamespaces,
+		ListDescendantsTimeout:             clusterListDescendantsTimeout,
+		InfrastructureNotFoundRequeueAfter: clusterInfrastructureNotFoundRequeueAfter,
+		InfrastructureNotFoundThreshold:    clusterInfrastructureNotFoundThreshold,
+		RecreateDeletedInfrastructure:      clusterRecreateDeletedInfrastructure,
+		ControlPlaneDeletingGracePeriod:    clusterControlPlaneDeletingGracePeriod,
+		ControlPlaneResyncPeriod:           clusterControlPlaneResyncPeriod,
+		ResetControlPlaneInitialized:       clusterResetControlPlaneInitialized,
+		MaxDescendantsWarnThreshold:        clusterMaxDescendantsWarnThreshold,
+		MaxRequeueAfter:                    clusterMaxRequeueAfter,
+		PhaseFromConditions:                clusterPhaseFromConditions,
+		BootstrapConfigKinds:               bootstrapConfigKinds,
+		LegacyFinalizers:                   clusterLegacyFinalizers,
+		ReadOnly:                           clusterReadOnly,
+		ExportDescendantTopology:           clusterExportDescendantTopology,
+		DescendantChurnThreshold:           clusterDescendantChurnThreshold,
 	}).SetupWithManager(mgr, concurrency(clusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
@@ -339,3 +503,28 @@ func setupWebhooks(mgr ctrl.Manager) {
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
+
+// conditionTypes converts the given condition types, as set by a flag.
+func conditionTypes(types []string) []clusterv1alpha3.ConditionType {
+	if len(types) == 0 {
+		return nil
+	}
+	conditionTypes := make([]clusterv1alpha3.ConditionType, len(types))
+	for i := range types {
+		conditionTypes[i] = clusterv1alpha3.ConditionType(types[i])
+	}
+	return conditionTypes
+}
+
+// groupVersionKinds parses the given kinds, as set by a flag in the Kind.version.group format.
+func groupVersionKinds(kinds []string) ([]schema.GroupVersionKind, error) {
+	gvks := make([]schema.GroupVersionKind, 0, len(kinds))
+	for _, kind := range kinds {
+		gvk, _ := schema.ParseKindArg(kind)
+		if gvk == nil {
+			return nil, errors.Errorf("invalid kind %q: must be in the Kind.version.group format", kind)
+		}
+		gvks = append(gvks, *gvk)
+	}
+	return gvks, nil
+}
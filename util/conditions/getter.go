@@ -117,32 +117,119 @@ func GetLastTransitionTime(from Getter, t clusterv1.ConditionType) *metav1.Time
 // summary returns a Ready condition with the summary of all the conditions existing
 // on an object. If the object does not have other conditions, no summary condition is generated.
 func summary(from Getter, options ...MergeOption) *clusterv1.Condition {
-	conditions := from.GetConditions()
+	mergeOpt := &mergeOptions{}
+	for _, o := range options {
+		o(mergeOpt)
+	}
 
-	conditionsInScope := make([]localizedCondition, 0, len(conditions))
+	// Identifies the conditions in scope for the Summary by taking all the existing conditions except Ready,
+	// or, if a list of conditions types is specified, only the conditions in that list.
+	conditions := from.GetConditions()
+	conditionsInScope := make([]localizedCondition, 0, len(conditions)+len(mergeOpt.mustBeTrueConditions))
 	for i := range conditions {
 		c := conditions[i]
-		if c.Type != clusterv1.ReadyCondition {
-			conditionsInScope = append(conditionsInScope, localizedCondition{
-				Condition: &c,
-				Getter:    from,
-			})
+		if c.Type == clusterv1.ReadyCondition {
+			continue
+		}
+
+		if mergeOpt.conditionTypes != nil && !hasConditionType(mergeOpt.conditionTypes, c.Type) {
+			continue
+		}
+
+		if hasConditionType(mergeOpt.negativePolarityConditions, c.Type) {
+			c = invertPolarity(c)
+		}
+
+		if hasConditionType(mergeOpt.mustBeTrueConditions, c.Type) && c.Status == corev1.ConditionUnknown {
+			c.Status = corev1.ConditionFalse
+			if c.Severity == clusterv1.ConditionSeverityNone {
+				c.Severity = clusterv1.ConditionSeverityWarning
+			}
 		}
+
+		conditionsInScope = append(conditionsInScope, localizedCondition{
+			Condition: &c,
+			Getter:    from,
+		})
 	}
 
-	mergeOpt := &mergeOptions{}
-	for _, o := range options {
-		o(mergeOpt)
+	// Must be true conditions not yet reported on the object are considered as False conditions with Severity=Warning,
+	// so they can't be outranked by True conditions.
+	for _, t := range mergeOpt.mustBeTrueConditions {
+		if t == clusterv1.ReadyCondition || Has(from, t) {
+			continue
+		}
+		if mergeOpt.conditionTypes != nil && !hasConditionType(mergeOpt.conditionTypes, t) {
+			continue
+		}
+		conditionsInScope = append(conditionsInScope, localizedCondition{
+			Condition: FalseCondition(t, MustBeTrueConditionNotFoundReason, clusterv1.ConditionSeverityWarning, "Condition %s not yet reported", t),
+			Getter:    from,
+		})
 	}
+
 	return merge(conditionsInScope, clusterv1.ReadyCondition, mergeOpt)
 }
 
+// invertPolarity returns a copy of a negative polarity condition with the status inverted, so it
+// can be merged together with positive polarity conditions.
+func invertPolarity(c clusterv1.Condition) clusterv1.Condition {
+	switch c.Status {
+	case corev1.ConditionTrue:
+		c.Status = corev1.ConditionFalse
+		if c.Severity == clusterv1.ConditionSeverityNone {
+			c.Severity = clusterv1.ConditionSeverityWarning
+		}
+	case corev1.ConditionFalse:
+		c.Status = corev1.ConditionTrue
+		c.Severity = clusterv1.ConditionSeverityNone
+	}
+	return c
+}
+
+// hasConditionType returns true if the given condition type is included in the list.
+func hasConditionType(types []clusterv1.ConditionType, t clusterv1.ConditionType) bool {
+	for _, ct := range types {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+// FilteredGetter returns a Getter exposing only the conditions of the given types from the source Getter,
+// plus a Ready condition summarizing them; this allows to control which conditions of an object are taken into
+// account e.g. when mirroring its Ready condition.
+func FilteredGetter(from Getter, t ...clusterv1.ConditionType) Getter {
+	return &filteredGetter{Getter: from, conditionTypes: t}
+}
+
+type filteredGetter struct {
+	Getter
+	conditionTypes []clusterv1.ConditionType
+}
+
+// GetConditions returns the filtered list of conditions, plus the Ready condition summarizing them, if any.
+func (f *filteredGetter) GetConditions() clusterv1.Conditions {
+	conditions := clusterv1.Conditions{}
+	for _, c := range f.Getter.GetConditions() {
+		if c.Type != clusterv1.ReadyCondition && hasConditionType(f.conditionTypes, c.Type) {
+			conditions = append(conditions, c)
+		}
+	}
+	if ready := summary(f.Getter, WithConditions(f.conditionTypes...)); ready != nil {
+		conditions = append(conditions, *ready)
+	}
+	return conditions
+}
+
 // mirrorOptions allows to set options for the mirror operation.
 type mirrorOptions struct {
 	fallbackTo       *bool
 	fallbackReason   string
 	fallbackSeverity clusterv1.ConditionSeverity
 	fallbackMessage  string
+	severityMapping  func(clusterv1.ConditionSeverity) clusterv1.ConditionSeverity
 }
 
 // MirrorOptions defines an option for mirroring conditions.
@@ -159,6 +246,14 @@ func WithFallbackValue(fallbackValue bool, reason string, severity clusterv1.Con
 	}
 }
 
+// WithSeverityMapping specify a function mapping the severity of the mirrored condition, e.g. for downgrading
+// the severity reported by the dependent object; the mapping does not apply to the fallback value.
+func WithSeverityMapping(mapping func(clusterv1.ConditionSeverity) clusterv1.ConditionSeverity) MirrorOptions {
+	return func(c *mirrorOptions) {
+		c.severityMapping = mapping
+	}
+}
+
 // mirror mirrors the Ready condition from a dependent object into the target condition;
 // if the Ready condition does not exists in the source object, no target conditions is generated.
 func mirror(from Getter, targetCondition clusterv1.ConditionType, options ...MirrorOptions) *clusterv1.Condition {
@@ -169,6 +264,10 @@ func mirror(from Getter, targetCondition clusterv1.ConditionType, options ...Mir
 
 	condition := Get(from, clusterv1.ReadyCondition)
 
+	if mirrorOpt.severityMapping != nil && condition != nil && condition.Severity != clusterv1.ConditionSeverityNone {
+		condition.Severity = mirrorOpt.severityMapping(condition.Severity)
+	}
+
 	if mirrorOpt.fallbackTo != nil && condition == nil {
 		switch *mirrorOpt.fallbackTo {
 		case true:
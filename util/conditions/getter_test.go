@@ -130,15 +130,57 @@ func TestMirror(t *testing.T) {
 	}
 }
 
+func TestMirrorWithSeverityMapping(t *testing.T) {
+	g := NewWithT(t)
+
+	toWarning := func(clusterv1.ConditionSeverity) clusterv1.ConditionSeverity {
+		return clusterv1.ConditionSeverityWarning
+	}
+
+	// The severity of the mirrored condition is mapped.
+	readyError := FalseCondition(clusterv1.ReadyCondition, "reason falseError1", clusterv1.ConditionSeverityError, "message falseError1")
+	got := mirror(getterWithConditions(readyError), "foo", WithSeverityMapping(toWarning))
+	g.Expect(got).To(haveSameStateOf(FalseCondition("foo", "reason falseError1", clusterv1.ConditionSeverityWarning, "message falseError1")))
+
+	// Conditions without a severity, and fallback values, are not mapped.
+	got = mirror(getterWithConditions(TrueCondition(clusterv1.ReadyCondition)), "foo", WithSeverityMapping(toWarning))
+	g.Expect(got).To(haveSameStateOf(TrueCondition("foo")))
+
+	got = mirror(getterWithConditions(), "foo", WithSeverityMapping(toWarning),
+		WithFallbackValue(false, "reason falseInfo1", clusterv1.ConditionSeverityInfo, "message falseInfo1"))
+	g.Expect(got).To(haveSameStateOf(FalseCondition("foo", "reason falseInfo1", clusterv1.ConditionSeverityInfo, "message falseInfo1")))
+}
+
+func TestFilteredGetter(t *testing.T) {
+	g := NewWithT(t)
+
+	readyFalse := FalseCondition(clusterv1.ReadyCondition, "reason falseError1", clusterv1.ConditionSeverityError, "message falseError1")
+	from := getterWithConditions(readyFalse, true1, falseError1)
+
+	// Only the allowlisted conditions are exposed, and the Ready condition is computed on them.
+	filtered := FilteredGetter(from, "true1")
+	g.Expect(filtered.GetConditions()).To(HaveLen(2))
+	g.Expect(Has(filtered, "falseError1")).To(BeFalse())
+	g.Expect(IsTrue(filtered, clusterv1.ReadyCondition)).To(BeTrue())
+	g.Expect(mirror(filtered, "bar")).To(haveSameStateOf(TrueCondition("bar")))
+
+	// If none of the allowlisted conditions exist, there is no Ready condition to be mirrored.
+	filtered = FilteredGetter(from, "foo")
+	g.Expect(filtered.GetConditions()).To(BeEmpty())
+	g.Expect(mirror(filtered, "bar")).To(BeNil())
+}
+
 func TestSummary(t *testing.T) {
 	foo := TrueCondition("foo")
 	bar := FalseCondition("bar", "reason falseInfo1", clusterv1.ConditionSeverityInfo, "message falseInfo1")
+	baz := UnknownCondition("baz", "reason unknown1", "message unknown1")
 	existingReady := FalseCondition(clusterv1.ReadyCondition, "reason falseError1", clusterv1.ConditionSeverityError, "message falseError1") //NB. existing ready has higher priority than other conditions
 
 	tests := []struct {
-		name string
-		from Getter
-		want *clusterv1.Condition
+		name    string
+		from    Getter
+		options []MergeOption
+		want    *clusterv1.Condition
 	}{
 		{
 			name: "Returns nil when there are no conditions to summarize",
@@ -155,13 +197,66 @@ func TestSummary(t *testing.T) {
 			from: getterWithConditions(existingReady, foo, bar),
 			want: FalseCondition(clusterv1.ReadyCondition, "reason falseInfo1", clusterv1.ConditionSeverityInfo, "message falseInfo1"),
 		},
+		{
+			name:    "Returns ready condition with the summary of selected conditions",
+			from:    getterWithConditions(foo, bar),
+			options: []MergeOption{WithConditions("foo")},
+			want:    TrueCondition(clusterv1.ReadyCondition),
+		},
+		{
+			name:    "Returns nil when none of the selected conditions exists",
+			from:    getterWithConditions(foo, bar),
+			options: []MergeOption{WithConditions("baz")},
+			want:    nil,
+		},
+		{
+			name:    "Returns ready condition with negative polarity conditions inverted (True)",
+			from:    getterWithConditions(foo, bar),
+			options: []MergeOption{WithConditions("foo"), WithNegativePolarityConditions("foo")},
+			want:    FalseCondition(clusterv1.ReadyCondition, "", clusterv1.ConditionSeverityWarning, ""),
+		},
+		{
+			name:    "Returns ready condition with negative polarity conditions inverted (False)",
+			from:    getterWithConditions(foo, bar),
+			options: []MergeOption{WithNegativePolarityConditions("bar")},
+			want:    TrueCondition(clusterv1.ReadyCondition),
+		},
+		{
+			name: "Returns ready condition outranking Unknown conditions",
+			from: getterWithConditions(foo, baz),
+			want: TrueCondition(clusterv1.ReadyCondition),
+		},
+		{
+			name:    "Returns ready condition with must be true conditions failing (Unknown)",
+			from:    getterWithConditions(foo, baz),
+			options: []MergeOption{WithMustBeTrueConditions("baz")},
+			want:    FalseCondition(clusterv1.ReadyCondition, "reason unknown1", clusterv1.ConditionSeverityWarning, "message unknown1"),
+		},
+		{
+			name:    "Returns ready condition with must be true conditions failing (not existing)",
+			from:    getterWithConditions(foo),
+			options: []MergeOption{WithMustBeTrueConditions("baz")},
+			want:    FalseCondition(clusterv1.ReadyCondition, MustBeTrueConditionNotFoundReason, clusterv1.ConditionSeverityWarning, "Condition baz not yet reported"),
+		},
+		{
+			name:    "Returns ready condition with must be true conditions failing on an object without conditions",
+			from:    getterWithConditions(),
+			options: []MergeOption{WithMustBeTrueConditions("baz")},
+			want:    FalseCondition(clusterv1.ReadyCondition, MustBeTrueConditionNotFoundReason, clusterv1.ConditionSeverityWarning, "Condition baz not yet reported"),
+		},
+		{
+			name:    "Returns ready condition with must be true conditions True",
+			from:    getterWithConditions(foo, baz),
+			options: []MergeOption{WithMustBeTrueConditions("foo")},
+			want:    TrueCondition(clusterv1.ReadyCondition),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got := summary(tt.from)
+			got := summary(tt.from, tt.options...)
 			if tt.want == nil {
 				g.Expect(got).To(BeNil())
 				return
@@ -26,14 +26,56 @@ import (
 // mergeOptions allows to set strategies for merging a set of conditions into a single condition,
 // and more specifically for computing the target Reason and the target Message.
 type mergeOptions struct {
-	conditionOrder []clusterv1.ConditionType
-	addSourceRef   bool
-	stepCounter    int
+	conditionTypes             []clusterv1.ConditionType
+	negativePolarityConditions []clusterv1.ConditionType
+	mustBeTrueConditions       []clusterv1.ConditionType
+	conditionOrder             []clusterv1.ConditionType
+	addSourceRef               bool
+	stepCounter                int
 }
 
 // MergeOption defines an option for computing a summary of conditions.
 type MergeOption func(*mergeOptions)
 
+// WithConditions instructs merge about the condition types to consider when doing a merge operation;
+// if this option is not specified, all the conditions (excepts Ready) will be considered. This is required
+// so we can provide some guarantees about the semantic of the target condition without worrying about
+// side effects if someone or something adds custom conditions to the objects.
+//
+// NOTE: This option works only while generating the Summary condition.
+func WithConditions(t ...clusterv1.ConditionType) MergeOption {
+	return func(c *mergeOptions) {
+		c.conditionTypes = t
+	}
+}
+
+// WithNegativePolarityConditions instructs merge about the condition types with negative polarity,
+// e.g. Degraded, where Status=True signals a problem; those conditions are inverted before being merged,
+// so a True negative polarity condition is considered as a False condition with Severity=Warning
+// (or with its own severity, if set), and vice versa.
+//
+// NOTE: This option works only while generating the Summary condition.
+func WithNegativePolarityConditions(t ...clusterv1.ConditionType) MergeOption {
+	return func(c *mergeOptions) {
+		c.negativePolarityConditions = t
+	}
+}
+
+// MustBeTrueConditionNotFoundReason documents a must be true condition not yet reported on the object.
+const MustBeTrueConditionNotFoundReason = "ConditionNotFound"
+
+// WithMustBeTrueConditions instructs merge about the condition types which must be True for the target condition
+// to be True, e.g. because they are critical; those conditions with Status=Unknown or not existing, which would
+// otherwise be outranked by True conditions, are considered as False conditions with Severity=Warning (or with
+// their own severity, if set).
+//
+// NOTE: This option works only while generating the Summary condition.
+func WithMustBeTrueConditions(t ...clusterv1.ConditionType) MergeOption {
+	return func(c *mergeOptions) {
+		c.mustBeTrueConditions = t
+	}
+}
+
 // WithConditionOrder instructs merge about the condition order to be used when
 // merging conditions Reason and Message into the Target Condition.
 // The remaining conditions (not included in this list) will be sorted by type, and in case of
@@ -17,6 +17,7 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
@@ -109,40 +110,116 @@ func CreateSecret(ctx context.Context, c client.Client, cluster *clusterv1.Clust
 
 // CreateSecretWithOwner creates the Kubeconfig secret for the given cluster name, namespace, endpoint, and owner reference.
 func CreateSecretWithOwner(ctx context.Context, c client.Client, clusterName client.ObjectKey, endpoint string, owner metav1.OwnerReference) error {
-	clusterCA, err := secret.GetFromNamespacedName(ctx, c, clusterName, secret.ClusterCA)
+	out, err := generateKubeconfig(ctx, c, clusterName, endpoint)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return ErrDependentCertificateNotFound
-		}
 		return err
 	}
 
-	cert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
+	return c.Create(ctx, GenerateSecretWithOwner(clusterName, out, owner))
+}
+
+// RegenerateSecret replaces the Kubeconfig stored in the given secret with a new one generated for the given
+// cluster name, namespace, and endpoint.
+func RegenerateSecret(ctx context.Context, c client.Client, clusterName client.ObjectKey, endpoint string, configSecret *corev1.Secret) error {
+	out, err := generateKubeconfig(ctx, c, clusterName, endpoint)
 	if err != nil {
-		return errors.Wrap(err, "failed to decode CA Cert")
-	} else if cert == nil {
-		return errors.New("certificate not found in config")
+		return err
 	}
 
-	key, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
+	patch := client.MergeFrom(configSecret.DeepCopy())
+	if configSecret.Data == nil {
+		configSecret.Data = map[string][]byte{}
+	}
+	configSecret.Data[secret.KubeconfigDataName] = out
+	return c.Patch(ctx, configSecret, patch)
+}
+
+// VerifySecret returns an error if the Kubeconfig stored in the given secret can't be parsed, does not point
+// to the given endpoint or does not trust the CA of the given cluster, e.g. because the secret has been edited.
+func VerifySecret(ctx context.Context, c client.Reader, clusterName client.ObjectKey, endpoint string, configSecret *corev1.Secret) error {
+	cert, _, err := getClusterCA(ctx, c, clusterName)
 	if err != nil {
-		return errors.Wrap(err, "failed to decode private key")
-	} else if key == nil {
-		return errors.New("CA private key not found")
+		return err
+	}
+
+	data, ok := configSecret.Data[secret.KubeconfigDataName]
+	if !ok {
+		return errors.Errorf("missing key %q in secret data", secret.KubeconfigDataName)
+	}
+
+	cfg, err := clientcmd.Load(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse kubeconfig")
+	}
+	currentContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return errors.Errorf("current context %q not found in kubeconfig", cfg.CurrentContext)
+	}
+	if _, ok := cfg.AuthInfos[currentContext.AuthInfo]; !ok {
+		return errors.Errorf("user %q not found in kubeconfig", currentContext.AuthInfo)
+	}
+	currentCluster, ok := cfg.Clusters[currentContext.Cluster]
+	if !ok {
+		return errors.Errorf("cluster %q not found in kubeconfig", currentContext.Cluster)
+	}
+
+	if server := fmt.Sprintf("https://%s", endpoint); currentCluster.Server != server {
+		return errors.Errorf("kubeconfig server %q does not match %q", currentCluster.Server, server)
+	}
+
+	if !bytes.Equal(currentCluster.CertificateAuthorityData, certs.EncodeCertPEM(cert)) {
+		return errors.New("kubeconfig certificate authority does not match the cluster CA")
+	}
+
+	return nil
+}
+
+// generateKubeconfig returns a serialized Kubeconfig for the given cluster name, namespace, and endpoint,
+// signed by the cluster CA.
+func generateKubeconfig(ctx context.Context, c client.Reader, clusterName client.ObjectKey, endpoint string) ([]byte, error) {
+	cert, key, err := getClusterCA(ctx, c, clusterName)
+	if err != nil {
+		return nil, err
 	}
 
 	server := fmt.Sprintf("https://%s", endpoint)
 	cfg, err := New(clusterName.Name, server, cert, key)
 	if err != nil {
-		return errors.Wrap(err, "failed to generate a kubeconfig")
+		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
 	}
 
 	out, err := clientcmd.Write(*cfg)
 	if err != nil {
-		return errors.Wrap(err, "failed to serialize config to yaml")
+		return nil, errors.Wrap(err, "failed to serialize config to yaml")
 	}
+	return out, nil
+}
 
-	return c.Create(ctx, GenerateSecretWithOwner(clusterName, out, owner))
+// getClusterCA returns the CA certificate and private key of the given cluster.
+func getClusterCA(ctx context.Context, c client.Reader, clusterName client.ObjectKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	clusterCA, err := secret.GetFromNamespacedName(ctx, c, clusterName, secret.ClusterCA)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, ErrDependentCertificateNotFound
+		}
+		return nil, nil, err
+	}
+
+	cert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode CA Cert")
+	} else if cert == nil {
+		return nil, nil, errors.New("certificate not found in config")
+	}
+
+	key, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode private key")
+	} else if key == nil {
+		return nil, nil, errors.New("CA private key not found")
+	}
+
+	return cert, key, nil
 }
 
 // GenerateSecret returns a Kubernetes secret for the given Cluster and kubeconfig data.
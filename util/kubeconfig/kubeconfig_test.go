@@ -349,3 +349,49 @@ func TestCreateSecret(t *testing.T) {
 	g.Expect(restClient.CAData).To(Equal(certs.EncodeCertPEM(caCert)))
 	g.Expect(restClient.Host).To(Equal("https://localhost:8443"))
 }
+
+func TestVerifyAndRegenerateSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-ca",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			secret.TLSKeyDataName: certs.EncodePrivateKeyPEM(caKey),
+			secret.TLSCrtDataName: certs.EncodeCertPEM(caCert),
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(setupScheme(), caSecret)
+
+	clusterName := client.ObjectKey{Name: "test1", Namespace: "test"}
+	g.Expect(CreateSecretWithOwner(context.Background(), c, clusterName, "localhost:6443", metav1.OwnerReference{})).To(Succeed())
+
+	s := &corev1.Secret{}
+	key := client.ObjectKey{Name: "test1-kubeconfig", Namespace: "test"}
+	g.Expect(c.Get(context.Background(), key, s)).To(Succeed())
+	g.Expect(VerifySecret(context.Background(), c, clusterName, "localhost:6443", s)).To(Succeed())
+
+	// The Kubeconfig must point to the expected endpoint.
+	g.Expect(VerifySecret(context.Background(), c, clusterName, "localhost:443", s)).NotTo(Succeed())
+
+	// The Kubeconfig must trust the cluster CA.
+	g.Expect(VerifySecret(context.Background(), c, clusterName, "test-cluster-api:6443", validSecret)).NotTo(Succeed())
+
+	// The Kubeconfig must be parsable.
+	s.Data[secret.KubeconfigDataName] = []byte("tampered")
+	g.Expect(c.Update(context.Background(), s)).To(Succeed())
+	g.Expect(VerifySecret(context.Background(), c, clusterName, "localhost:6443", s)).NotTo(Succeed())
+
+	g.Expect(RegenerateSecret(context.Background(), c, clusterName, "localhost:6443", s)).To(Succeed())
+	g.Expect(c.Get(context.Background(), key, s)).To(Succeed())
+	g.Expect(VerifySecret(context.Background(), c, clusterName, "localhost:6443", s)).To(Succeed())
+}
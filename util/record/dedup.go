@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+// maxDeduplicatedEvents is the maximum number of distinct events tracked for deduplication;
+// when the limit is reached, the least recently emitted events are forgotten.
+const maxDeduplicatedEvents = 4096
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// deduplicatingRecorder is an EventRecorder suppressing identical events, i.e. events with the same
+// type, reason and message, emitted for the same object within a given window.
+type deduplicatingRecorder struct {
+	record.EventRecorder
+
+	window time.Duration
+	seen   *cache.LRUExpireCache
+}
+
+// NewDeduplicatingRecorder returns an EventRecorder wrapping the given recorder, which emits an event
+// only once per window for each object, type, reason and message; this avoids flooding the events
+// of an object when it is reconciled repeatedly while blocked on the same problem.
+func NewDeduplicatingRecorder(recorder record.EventRecorder, window time.Duration) record.EventRecorder {
+	return newDeduplicatingRecorder(recorder, window, realClock{})
+}
+
+func newDeduplicatingRecorder(recorder record.EventRecorder, window time.Duration, clock cache.Clock) *deduplicatingRecorder {
+	return &deduplicatingRecorder{
+		EventRecorder: recorder,
+		window:        window,
+		seen:          cache.NewLRUExpireCacheWithClock(maxDeduplicatedEvents, clock),
+	}
+}
+
+// Event emits the event, unless an identical event has been emitted for the object within the window.
+func (r *deduplicatingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if !r.shouldEmit(object, eventtype, reason, message) {
+		return
+	}
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf is just like Event, but with Sprintf for the message field.
+func (r *deduplicatingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is just like Eventf, but with annotations attached.
+func (r *deduplicatingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if !r.shouldEmit(object, eventtype, reason, message) {
+		return
+	}
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+}
+
+// shouldEmit returns true if an identical event has not been emitted for the object within the window,
+// and records the event as emitted.
+func (r *deduplicatingRecorder) shouldEmit(object runtime.Object, eventtype, reason, message string) bool {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return true
+	}
+
+	key := fmt.Sprintf("%s/%s/%s/%s", accessor.GetUID(), eventtype, reason, message)
+	if _, ok := r.seen.Get(key); ok {
+		return false
+	}
+	r.seen.Add(key, struct{}{}, r.window)
+	return true
+}
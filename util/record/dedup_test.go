@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestDeduplicatingRecorder(t *testing.T) {
+	g := NewWithT(t)
+
+	clock := &fakeClock{now: time.Now()}
+	fakeRecorder := record.NewFakeRecorder(32)
+	recorder := newDeduplicatingRecorder(fakeRecorder, 5*time.Minute, clock)
+
+	obj1 := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: "uid-1"}}
+	obj2 := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar", UID: "uid-2"}}
+
+	recorder.Eventf(obj1, corev1.EventTypeWarning, "DeletionBlocked", "waiting for %d descendants", 3)
+	g.Expect(fakeRecorder.Events).To(HaveLen(1))
+
+	// Identical events within the window are suppressed.
+	recorder.Eventf(obj1, corev1.EventTypeWarning, "DeletionBlocked", "waiting for %d descendants", 3)
+	recorder.Event(obj1, corev1.EventTypeWarning, "DeletionBlocked", "waiting for 3 descendants")
+	g.Expect(fakeRecorder.Events).To(HaveLen(1))
+
+	// Events with a different message, or for a different object, are emitted.
+	recorder.Eventf(obj1, corev1.EventTypeWarning, "DeletionBlocked", "waiting for %d descendants", 2)
+	recorder.Eventf(obj2, corev1.EventTypeWarning, "DeletionBlocked", "waiting for %d descendants", 3)
+	g.Expect(fakeRecorder.Events).To(HaveLen(3))
+
+	// Once the window expires, the event is emitted again.
+	clock.now = clock.now.Add(6 * time.Minute)
+	recorder.Eventf(obj1, corev1.EventTypeWarning, "DeletionBlocked", "waiting for %d descendants", 3)
+	g.Expect(fakeRecorder.Events).To(HaveLen(4))
+}
@@ -41,6 +41,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/container"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -221,6 +222,51 @@ func IsControlPlaneMachine(machine *clusterv1.Machine) bool {
 	return ok
 }
 
+// DescendantTier defines the tier of a Cluster descendant.
+type DescendantTier string
+
+const (
+	// DescendantTierControlPlane is the tier of the descendants running the control plane.
+	DescendantTierControlPlane = DescendantTier("ControlPlane")
+
+	// DescendantTierWorker is the tier of the descendants running the workloads.
+	DescendantTierWorker = DescendantTier("Worker")
+
+	// DescendantTierUnknown is the tier of the descendants which can't be classified.
+	DescendantTierUnknown = DescendantTier("Unknown")
+)
+
+// ClassifyDescendant returns the tier of a Cluster descendant; Machines belong to the control plane tier
+// if they have the control plane label, and to the worker tier otherwise, while other objects are not classified.
+func ClassifyDescendant(obj runtime.Object) DescendantTier {
+	machine, ok := obj.(*clusterv1.Machine)
+	if !ok {
+		return DescendantTierUnknown
+	}
+	if IsControlPlaneMachine(machine) {
+		return DescendantTierControlPlane
+	}
+	return DescendantTierWorker
+}
+
+// IsMachinePoolMachine returns true if a Machine is backed by a MachinePool, i.e. it has the MachinePoolNameLabel
+// or it is owned by a MachinePool.
+func IsMachinePoolMachine(machine *clusterv1.Machine) bool {
+	if _, ok := machine.Labels[expv1.MachinePoolNameLabel]; ok {
+		return true
+	}
+	for _, ref := range machine.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if gv.Group == expv1.GroupVersion.Group && ref.Kind == "MachinePool" {
+			return true
+		}
+	}
+	return false
+}
+
 // IsNodeReady returns true if a node is ready.
 func IsNodeReady(node *v1.Node) bool {
 	for _, condition := range node.Status.Conditions {
@@ -273,6 +319,46 @@ func ObjectKey(object metav1.Object) client.ObjectKey {
 	}
 }
 
+// GVKObjectKey identifies an object by its GroupVersionKind, namespace and name.
+type GVKObjectKey struct {
+	schema.GroupVersionKind
+	client.ObjectKey
+}
+
+// ObjectKeyWithGVK returns a GVKObjectKey for the object of the given GroupVersionKind.
+func ObjectKeyWithGVK(gvk schema.GroupVersionKind, object metav1.Object) GVKObjectKey {
+	return GVKObjectKey{
+		GroupVersionKind: gvk,
+		ObjectKey:        ObjectKey(object),
+	}
+}
+
+// RequestSet is an ordered set of reconcile.Request, deduplicated by GVKObjectKey.
+// The zero value is ready to use.
+type RequestSet struct {
+	keys     map[GVKObjectKey]struct{}
+	requests []reconcile.Request
+}
+
+// Insert adds a request for the given key to the set, if not already present.
+// It returns true if the request has been added.
+func (s *RequestSet) Insert(key GVKObjectKey) bool {
+	if s.keys == nil {
+		s.keys = map[GVKObjectKey]struct{}{}
+	}
+	if _, ok := s.keys[key]; ok {
+		return false
+	}
+	s.keys[key] = struct{}{}
+	s.requests = append(s.requests, reconcile.Request{NamespacedName: key.ObjectKey})
+	return true
+}
+
+// Requests returns the requests in the set, in insertion order.
+func (s *RequestSet) Requests() []reconcile.Request {
+	return s.requests
+}
+
 // ClusterToInfrastructureMapFunc returns a handler.ToRequestsFunc that watches for
 // Cluster events and returns reconciliation requests for an infrastructure provider object.
 func ClusterToInfrastructureMapFunc(gvk schema.GroupVersionKind) handler.ToRequestsFunc {
@@ -626,6 +712,25 @@ func IsSupportedVersionSkew(a, b semver.Version) bool {
 	return b.Minor-a.Minor <= 1
 }
 
+// LowestNonZeroResult compares two reconciliation results and returns the one requeuing the soonest;
+// an empty result, i.e. no requeue, is returned only if both results are empty.
+func LowestNonZeroResult(i, j ctrl.Result) ctrl.Result {
+	switch {
+	case i == (ctrl.Result{}):
+		return j
+	case j == (ctrl.Result{}):
+		return i
+	case i.RequeueAfter == 0:
+		return i
+	case j.RequeueAfter == 0:
+		return j
+	case i.RequeueAfter <= j.RequeueAfter:
+		return i
+	default:
+		return j
+	}
+}
+
 // NewDelegatingClientFunc returns a manager.NewClientFunc to be used when creating
 // a new controller runtime manager.
 //
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
 	. "github.com/onsi/gomega"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha3"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -823,3 +825,189 @@ func TestIsSupportedVersionSkew(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyDescendant(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  runtime.Object
+		want DescendantTier
+	}{
+		{
+			name: "control plane Machine",
+			obj: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.MachineControlPlaneLabelName: "",
+					},
+				},
+			},
+			want: DescendantTierControlPlane,
+		},
+		{
+			name: "worker Machine",
+			obj: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName: "test-cluster",
+					},
+				},
+			},
+			want: DescendantTierWorker,
+		},
+		{
+			name: "MachineSet",
+			obj:  &clusterv1.MachineSet{},
+			want: DescendantTierUnknown,
+		},
+		{
+			name: "Secret",
+			obj:  &corev1.Secret{},
+			want: DescendantTierUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(ClassifyDescendant(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsMachinePoolMachine(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine *clusterv1.Machine
+		want    bool
+	}{
+		{
+			name: "Machine with the MachinePool name label",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						expv1.MachinePoolNameLabel: "mp1",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Machine owned by a MachinePool",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: expv1.GroupVersion.String(),
+							Kind:       "MachinePool",
+							Name:       "mp1",
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Machine owned by a MachinePool of another group",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "foo.example.com/v1",
+							Kind:       "MachinePool",
+							Name:       "mp1",
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Machine owned by a MachineSet",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: clusterv1.GroupVersion.String(),
+							Kind:       "MachineSet",
+							Name:       "ms1",
+						},
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsMachinePoolMachine(tt.machine)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestLowestNonZeroResult(t *testing.T) {
+	tests := []struct {
+		name string
+		i    ctrl.Result
+		j    ctrl.Result
+		want ctrl.Result
+	}{
+		{
+			name: "both empty",
+			want: ctrl.Result{},
+		},
+		{
+			name: "i empty",
+			j:    ctrl.Result{RequeueAfter: time.Minute},
+			want: ctrl.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name: "j empty",
+			i:    ctrl.Result{RequeueAfter: time.Minute},
+			want: ctrl.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name: "immediate requeue wins",
+			i:    ctrl.Result{RequeueAfter: time.Minute},
+			j:    ctrl.Result{Requeue: true},
+			want: ctrl.Result{Requeue: true},
+		},
+		{
+			name: "lowest requeue after wins",
+			i:    ctrl.Result{Requeue: true, RequeueAfter: time.Minute},
+			j:    ctrl.Result{RequeueAfter: time.Second},
+			want: ctrl.Result{RequeueAfter: time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(LowestNonZeroResult(tt.i, tt.j)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRequestSet(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterGVK := clusterv1.GroupVersion.WithKind("Cluster")
+	machineGVK := clusterv1.GroupVersion.WithKind("Machine")
+	obj := &metav1.ObjectMeta{Namespace: "default", Name: "foo"}
+	other := &metav1.ObjectMeta{Namespace: "default", Name: "bar"}
+
+	set := RequestSet{}
+	g.Expect(set.Insert(ObjectKeyWithGVK(clusterGVK, obj))).To(BeTrue())
+	g.Expect(set.Insert(ObjectKeyWithGVK(clusterGVK, other))).To(BeTrue())
+	// Same kind and key, should be deduplicated.
+	g.Expect(set.Insert(ObjectKeyWithGVK(clusterGVK, obj))).To(BeFalse())
+	// Same key but a different kind, should be considered a different entry.
+	g.Expect(set.Insert(ObjectKeyWithGVK(machineGVK, obj))).To(BeTrue())
+
+	g.Expect(set.Requests()).To(Equal([]reconcile.Request{
+		{NamespacedName: client.ObjectKey{Namespace: "default", Name: "foo"}},
+		{NamespacedName: client.ObjectKey{Namespace: "default", Name: "bar"}},
+		{NamespacedName: client.ObjectKey{Namespace: "default", Name: "foo"}},
+	}))
+}